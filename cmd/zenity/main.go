@@ -8,6 +8,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 	"time"
 
@@ -28,11 +29,22 @@ var (
 	colorSelectionDlg bool
 
 	// General options
-	title string
+	title     string
+	attach    string
+	windowID  int
+	modal     bool
+	width     int
+	height    int
+	name      string
+	class     string
+	display   string
+	timeout   int
+	separator string
 
 	// Message options
 	text          string
 	icon          string
+	windowIcon    string
 	okLabel       string
 	cancelLabel   string
 	extraButton   string
@@ -72,11 +84,8 @@ func main() {
 	flag.Parse()
 	validateFlags()
 	opts := loadFlags()
-	zenutil.Command = true
-	if zenutil.Timeout > 0 {
-		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(zenutil.Timeout)*time.Second)
-		opts = append(opts, zenity.Context(ctx))
-		_ = cancel
+	if runtime.GOOS != "windows" {
+		opts = append(opts, zenity.Context(zenutil.WithCommand(context.Background())))
 	}
 
 	switch {
@@ -121,11 +130,18 @@ func setupFlags() {
 
 	// General options
 	flag.StringVar(&title, "title", "", "Set the dialog title")
-	flag.StringVar(&icon, "window-icon", "", "Set the window icon (error, info, question, warning)")
+	flag.StringVar(&windowIcon, "window-icon", "", "Set the window icon (error, info, question, warning, or a file or icon name)")
+	flag.StringVar(&attach, "attach", "", "Set the parent window to attach to")
+	flag.BoolVar(&modal, "modal", false, "Set the modal hint")
+	flag.IntVar(&width, "width", 0, "Set the width")
+	flag.IntVar(&height, "height", 0, "Set the height")
+	flag.StringVar(&display, "display", "", "X display to use")
+	flag.StringVar(&name, "name", "", "Program name as used by the window manager")
+	flag.StringVar(&class, "class", "", "Program class as used by the window manager")
 
 	// Message options
 	flag.StringVar(&text, "text", "", "Set the dialog text")
-	flag.StringVar(&icon, "icon-name", "", "Set the dialog icon (error, info, question, warning)")
+	flag.StringVar(&icon, "icon-name", "", "Set the dialog icon (error, info, question, warning, or an icon name)")
 	flag.StringVar(&okLabel, "ok-label", "", "Set the label of the OK button")
 	flag.StringVar(&cancelLabel, "cancel-label", "", "Set the label of the Cancel button")
 	flag.StringVar(&extraButton, "extra-button", "", "Add an extra button")
@@ -154,8 +170,8 @@ func setupFlags() {
 	}
 
 	// Internal options
-	flag.IntVar(&zenutil.Timeout, "timeout", 0, "Set dialog timeout in seconds")
-	flag.StringVar(&zenutil.Separator, "separator", "|", "Set output separator character")
+	flag.IntVar(&timeout, "timeout", 0, "Set dialog timeout in seconds")
+	flag.StringVar(&separator, "separator", "|", "Set output separator character")
 }
 
 func validateFlags() {
@@ -184,6 +200,15 @@ func validateFlags() {
 	if n != 1 {
 		flag.Usage()
 	}
+	if attach != "" {
+		id, err := strconv.ParseInt(attach, 0, 0)
+		if err != nil {
+			os.Stderr.WriteString(`invalid value "` + attach + `" for flag -attach: ` +
+				err.(*strconv.NumError).Err.Error() + zenutil.LineBreak)
+			flag.Usage()
+		}
+		windowID = int(id)
+	}
 }
 
 func loadFlags() []zenity.Option {
@@ -192,22 +217,40 @@ func loadFlags() []zenity.Option {
 	// General options
 
 	opts = append(opts, zenity.Title(title))
+	if attach != "" {
+		opts = append(opts, zenity.Attach(windowID))
+	}
+	if modal {
+		opts = append(opts, zenity.Modal())
+	}
+	opts = append(opts, zenity.Width(width))
+	opts = append(opts, zenity.Height(height))
+	opts = append(opts, zenity.WindowClass(name, class))
+	if display != "" {
+		opts = append(opts, zenity.Display(display))
+	}
+	if timeout > 0 {
+		opts = append(opts, zenity.Timeout(time.Duration(timeout)*time.Second))
+	}
 
 	// Message options
 
-	var ico zenity.DialogIcon
-	switch icon {
-	case "error", "dialog-error":
-		ico = zenity.ErrorIcon
-	case "info", "dialog-information":
-		ico = zenity.InfoIcon
-	case "question", "dialog-question":
-		ico = zenity.QuestionIcon
-	case "important", "warning", "dialog-warning":
-		ico = zenity.WarningIcon
+	switch ico := stockIcon(icon); {
+	case ico != 0:
+		opts = append(opts, zenity.Icon(ico))
+	case icon != "":
+		opts = append(opts, zenity.IconName(icon))
+	case stockIcon(windowIcon) != 0:
+		// Windows and macOS only have dialog icons.
+		opts = append(opts, zenity.Icon(stockIcon(windowIcon)))
+	}
+	if ico := stockIcon(windowIcon); ico != 0 {
+		opts = append(opts, zenity.WindowIcon(ico))
+	} else if _, err := os.Stat(windowIcon); err == nil {
+		opts = append(opts, zenity.WindowIconFile(windowIcon))
+	} else if windowIcon != "" {
+		opts = append(opts, zenity.WindowIconName(windowIcon))
 	}
-
-	opts = append(opts, zenity.Icon(ico))
 	opts = append(opts, zenity.OKLabel(okLabel))
 	opts = append(opts, zenity.CancelLabel(cancelLabel))
 	opts = append(opts, zenity.ExtraButton(extraButton))
@@ -224,6 +267,7 @@ func loadFlags() []zenity.Option {
 	// File selection options
 
 	opts = append(opts, fileFilters)
+	opts = append(opts, zenity.Separator(separator))
 	if filename != "" {
 		opts = append(opts, zenity.Filename(ingestPath(filename)))
 	}
@@ -252,6 +296,20 @@ func loadFlags() []zenity.Option {
 	return opts
 }
 
+func stockIcon(name string) zenity.DialogIcon {
+	switch name {
+	case "error", "dialog-error":
+		return zenity.ErrorIcon
+	case "info", "dialog-information":
+		return zenity.InfoIcon
+	case "question", "dialog-question":
+		return zenity.QuestionIcon
+	case "important", "warning", "dialog-warning":
+		return zenity.WarningIcon
+	}
+	return 0
+}
+
 func errResult(err error) {
 	if os.IsTimeout(err) {
 		os.Exit(5)
@@ -295,7 +353,7 @@ func listResult(l []string, err error) {
 	if err != nil {
 		errResult(err)
 	}
-	os.Stdout.WriteString(strings.Join(l, zenutil.Separator))
+	os.Stdout.WriteString(strings.Join(l, separator))
 	os.Stdout.WriteString(zenutil.LineBreak)
 	if l == nil {
 		os.Exit(1)
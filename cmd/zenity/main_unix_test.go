@@ -0,0 +1,78 @@
+// +build !windows,!darwin
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	if os.Getenv("ZENITY_TEST_MAIN") != "" {
+		main()
+	}
+	os.Exit(m.Run())
+}
+
+// runMain runs the command with args, displaying dialogs with a stub that
+// reports version, writes out and exits with status. It returns what the
+// command writes and its exit status.
+func runMain(t *testing.T, name, version, out string, status int, args ...string) (string, int) {
+	stub := filepath.Join(t.TempDir(), name)
+	err := ioutil.WriteFile(stub, []byte(`#!/bin/sh
+if [ "$1" = --version ]; then
+	echo `+version+`
+	exit 0
+fi
+printf '%s\n' '`+out+`'
+exit `+strconv.Itoa(status)+`
+`), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Env = append(os.Environ(), "ZENITY_TEST_MAIN=1", "ZENITY_EXECUTABLE="+stub,
+		"DISPLAY=:0", "DBUS_SESSION_BUS_ADDRESS=unix:path=/dev/null")
+	stdout, err := cmd.Output()
+	if err, ok := err.(*exec.ExitError); ok {
+		return string(stdout), err.ExitCode()
+	} else if err != nil {
+		t.Fatal(err)
+	}
+	return string(stdout), 0
+}
+
+func TestSwitchedButtons(t *testing.T) {
+	// zenity 4 reports the button clicked, which is translated.
+	out, status := runMain(t, "zenity", "4.0.1", "Yes", 1,
+		"--question", "--text", "hi", "--extra-button", "Later")
+	if out != "" || status != 0 {
+		t.Errorf("Yes: got %q, %d; want \"\", 0", out, status)
+	}
+
+	out, status = runMain(t, "zenity", "4.0.1", "Later", 1,
+		"--question", "--text", "hi", "--extra-button", "Later")
+	if out != "Later\n" || status != 1 {
+		t.Errorf("Later: got %q, %d; want \"Later\\n\", 1", out, status)
+	}
+}
+
+func TestExtraButtonList(t *testing.T) {
+	// matedialog 1.16 has no extra buttons, so they are listed.
+	out, status := runMain(t, "matedialog", "1.16.0", "Later", 0,
+		"--question", "--text", "hi", "--extra-button", "Later")
+	if out != "Later\n" || status != 1 {
+		t.Errorf("Later: got %q, %d; want \"Later\\n\", 1", out, status)
+	}
+
+	out, status = runMain(t, "matedialog", "1.16.0", "Yes", 0,
+		"--question", "--text", "hi", "--extra-button", "Later")
+	if out != "" || status != 0 {
+		t.Errorf("Yes: got %q, %d; want \"\", 0", out, status)
+	}
+}
@@ -6,8 +6,11 @@ import "image/color"
 //
 // Returns nil on cancel.
 //
-// Valid options: Title, Color, ShowPalette.
+// Valid options: Title, WindowIcon, Color, ShowPalette.
 func SelectColor(options ...Option) (color.Color, error) {
+	if err := validate("SelectColor", applyOptions(options)); err != nil {
+		return nil, err
+	}
 	return selectColor(options)
 }
 
@@ -9,6 +9,7 @@ import (
 
 func selectColor(options []Option) (color.Color, error) {
 	opts := applyOptions(options)
+	defer withTimeout(&opts)()
 
 	var col color.Color
 	if opts.color != nil {
@@ -9,14 +9,25 @@ import (
 	"github.com/ncruces/zenity/internal/zenutil"
 )
 
-func selectColor(options []Option) (color.Color, error) {
+func selectColor(options []Option) (res color.Color, err error) {
 	opts := applyOptions(options)
+	err = withBackend(&opts, func() error {
+		res, err = selectColorWith(opts)
+		return err
+	})
+	return res, err
+}
+
+func selectColorWith(opts options) (color.Color, error) {
+	cleanup, err := writeIcons(&opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
 
 	args := []string{"--color-selection"}
 
-	if opts.title != "" {
-		args = append(args, "--title", opts.title)
-	}
+	args = appendGeneral(args, opts)
 	if opts.color != nil {
 		args = append(args, "--color", zenutil.UnparseColor(opts.color))
 	}
@@ -24,7 +35,7 @@ func selectColor(options []Option) (color.Color, error) {
 		args = append(args, "--show-palette")
 	}
 
-	out, err := zenutil.Run(opts.ctx, args)
+	out, err := run(opts, args)
 	if err, ok := err.(*exec.ExitError); ok && err.ExitCode() != 255 {
 		return nil, nil
 	}
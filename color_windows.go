@@ -22,6 +22,7 @@ func init() {
 
 func selectColor(options []Option) (color.Color, error) {
 	opts := applyOptions(options)
+	defer withTimeout(&opts)()
 
 	// load custom colors
 	colorsMutex.Lock()
@@ -30,6 +31,7 @@ func selectColor(options []Option) (color.Color, error) {
 
 	var args _CHOOSECOLORW
 	args.StructSize = uint32(unsafe.Sizeof(args))
+	args.Owner = ownerWindow(opts)
 	args.CustColors = &customColors
 
 	if opts.color != nil {
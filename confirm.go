@@ -0,0 +1,118 @@
+package zenity
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// ConfirmPhrase displays an entry dialog asking the user to type phrase to
+// confirm an action, described by text.
+//
+// Returns true only if the user types exactly phrase, and false on Cancel,
+// or if the typed text doesn't match after all attempts. On a mismatch, the
+// dialog is displayed again, with an error message.
+//
+// Without a dialog program or a display (or on Windows, which has no entry
+// dialog), ConfirmPhrase prompts the user on the terminal, if there is one.
+//
+// Valid options: Title, WindowIcon, OKLabel, CancelLabel, Markup, Attempts.
+func ConfirmPhrase(text, phrase string, options ...Option) (bool, error) {
+	opts := applyOptions(options)
+	if err := validate("ConfirmPhrase", opts); err != nil {
+		return false, err
+	}
+	attempts := opts.attempts
+	if attempts <= 0 {
+		attempts = 3
+	}
+
+	quoted := phrase
+	if opts.markup {
+		quoted = Escape(phrase)
+	}
+	text += "\n\nType “" + quoted + "” to confirm."
+
+	options = options[:len(options):len(options)]
+	for i := 0; i < attempts; i++ {
+		options := options
+		if i > 0 {
+			options = append(options, Detail("The text you typed doesn't match. Try again."))
+		}
+		typed, ok, err := readEntry(text, options)
+		if !ok || err != nil {
+			return false, err
+		}
+		if typed == phrase {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Attempts returns an Option to set how many times ConfirmPhrase asks for
+// the phrase (default 3).
+func Attempts(attempts int) Option {
+	return funcOption(func(o *options) { o.attempts = attempts })
+}
+
+// errNoEntry is returned by entry on platforms without an entry dialog.
+const errNoEntry = constError("zenity: entry dialog not supported")
+
+// readEntry displays an entry dialog, falling back to the terminal
+// if no dialog program is available.
+func readEntry(text string, options []Option) (string, bool, error) {
+	typed, ok, err := entry(text, options)
+	if err == errNoEntry || errors.Is(err, exec.ErrNotFound) || errors.Is(err, ErrNoDisplay) {
+		if isTerminal(os.Stdin) {
+			terminal.Lock()
+			defer terminal.Unlock()
+			if terminal.in == nil {
+				terminal.in = bufio.NewReader(os.Stdin)
+			}
+			return terminalEntry(terminal.in, os.Stderr, text, applyOptions(options))
+		}
+	}
+	return typed, ok, err
+}
+
+var terminal struct {
+	sync.Mutex
+	in *bufio.Reader
+}
+
+// terminalEntry prompts for a line of input on a terminal.
+// Returns false if the input ends without a line.
+func terminalEntry(in *bufio.Reader, out io.Writer, text string, opts options) (string, bool, error) {
+	if opts.markup {
+		text = plainText(text)
+	}
+	if opts.title != "" {
+		text = opts.title + "\n\n" + text
+	}
+	if opts.detail != "" {
+		text += "\n" + opts.detail
+	}
+	fmt.Fprintf(out, "%s\n> ", text)
+
+	line, err := in.ReadString('\n')
+	if err == io.EOF {
+		fmt.Fprintln(out)
+		if line == "" {
+			return "", false, nil
+		}
+	} else if err != nil {
+		return "", false, err
+	}
+	return strings.TrimRight(line, "\r\n"), true, nil
+}
+
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
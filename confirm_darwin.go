@@ -0,0 +1,45 @@
+package zenity
+
+import (
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/ncruces/zenity/internal/zenutil"
+)
+
+func entry(text string, options []Option) (string, bool, error) {
+	opts := applyOptions(options)
+	if opts.markup {
+		text = plainText(text)
+	}
+	if opts.detail != "" {
+		text += "\n\n" + opts.detail
+	}
+	data := zenutil.Entry{
+		Text:    text,
+		Title:   opts.title,
+		Timeout: int((opts.timeout + time.Second - 1) / time.Second),
+	}
+
+	if opts.okLabel != "" || opts.cancelLabel != "" {
+		if opts.okLabel == "" {
+			opts.okLabel = "OK"
+		}
+		if opts.cancelLabel == "" {
+			opts.cancelLabel = "Cancel"
+		}
+		data.Buttons = []string{opts.cancelLabel, opts.okLabel}
+		data.Default = 2
+		data.Cancel = 1
+	}
+
+	out, err := zenutil.Run(opts.ctx, "entry", data)
+	if err, ok := err.(*exec.ExitError); ok && err.ExitCode() == 1 {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return strings.TrimSuffix(string(out), "\n"), true, nil
+}
@@ -0,0 +1,30 @@
+package zenity
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+)
+
+func TestTerminalEntry(t *testing.T) {
+	in := bufio.NewReader(strings.NewReader("delete\r\ndelete prod"))
+	var out strings.Builder
+
+	typed, ok, err := terminalEntry(in, &out, "Delete <b>prod</b>?", options{markup: true})
+	if typed != "delete" || !ok || err != nil {
+		t.Errorf("terminalEntry() = %q, %v, %v", typed, ok, err)
+	}
+	if got := out.String(); got != "Delete prod?\n> " {
+		t.Errorf("prompt = %q", got)
+	}
+
+	typed, ok, err = terminalEntry(in, &out, "Delete prod?", options{})
+	if typed != "delete prod" || !ok || err != nil {
+		t.Errorf("terminalEntry() = %q, %v, %v", typed, ok, err)
+	}
+
+	typed, ok, err = terminalEntry(in, &out, "Delete prod?", options{})
+	if ok || err != nil {
+		t.Errorf("terminalEntry() = %q, %v, %v", typed, ok, err)
+	}
+}
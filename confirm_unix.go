@@ -0,0 +1,55 @@
+// +build !windows,!darwin
+
+package zenity
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// entryEscaper keeps underscores and backslashes in entry dialog text.
+var entryEscaper = strings.NewReplacer(`_`, `__`, `\`, `\\`)
+
+func entry(text string, options []Option) (res string, ok bool, err error) {
+	opts := applyOptions(options)
+	err = withBackend(&opts, func() error {
+		res, ok, err = entryWith(text, opts)
+		return err
+	})
+	return res, ok, err
+}
+
+func entryWith(text string, opts options) (string, bool, error) {
+	cleanup, err := writeIcons(&opts)
+	if err != nil {
+		return "", false, err
+	}
+	defer cleanup()
+
+	// Entry dialogs don't support markup: their text is set as a label with
+	// mnemonics, after expanding C escape sequences.
+	if opts.markup {
+		text = plainText(text)
+	}
+	if opts.detail != "" {
+		text += "\n\n" + opts.detail
+	}
+
+	args := []string{"--entry", "--text", entryEscaper.Replace(text)}
+	args = appendGeneral(args, opts)
+	if opts.okLabel != "" {
+		args = append(args, "--ok-label", opts.okLabel)
+	}
+	if opts.cancelLabel != "" {
+		args = append(args, "--cancel-label", opts.cancelLabel)
+	}
+
+	out, err := run(opts, args)
+	if err, ok := err.(*exec.ExitError); ok && err.ExitCode() != 255 {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return strings.TrimSuffix(string(out), "\n"), true, nil
+}
@@ -0,0 +1,78 @@
+// +build !windows,!darwin
+
+package zenity_test
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/ncruces/zenity"
+)
+
+func TestConfirmPhrase(t *testing.T) {
+	fakeSession(t)
+	path := stub(t, "zenity", "3.32.0", `
+if [ -e "$0.retry" ]; then
+	echo "delete prod"
+else
+	touch "$0.retry"
+	echo "delete"
+fi
+`)
+
+	ok, err := zenity.ConfirmPhrase("Delete the production database?", "delete prod",
+		zenity.Executable(path))
+	if !ok || err != nil {
+		t.Errorf("ConfirmPhrase() = %v, %v", ok, err)
+	}
+
+	args := stubArgs(t, path)
+	if !hasArg(args, "--entry") {
+		t.Errorf("missing --entry: %q", args)
+	}
+	if text := argValue(args, "--text"); !strings.Contains(text, "Type “delete prod” to confirm.") ||
+		!strings.Contains(text, "\n\nThe text you typed doesn't match.") {
+		t.Errorf("unexpected text: %q", text)
+	}
+}
+
+func TestConfirmPhraseMnemonics(t *testing.T) {
+	fakeSession(t)
+	path := stub(t, "zenity", "3.32.0", "echo drop_table")
+
+	ok, err := zenity.ConfirmPhrase(`Drop <b>C:\data</b>?`, "drop_table",
+		zenity.Executable(path), zenity.Markup())
+	if !ok || err != nil {
+		t.Errorf("ConfirmPhrase() = %v, %v", ok, err)
+	}
+
+	want := `Drop C:\\data?` + "\n\nType “drop__table” to confirm."
+	if text := argValue(stubArgs(t, path), "--text"); text != want {
+		t.Errorf("text = %q, want %q", text, want)
+	}
+}
+
+func TestConfirmPhraseAttempts(t *testing.T) {
+	fakeSession(t)
+	path := stub(t, "zenity", "3.32.0", `
+echo x >> "$0.count"
+echo "delete"
+`)
+
+	ok, err := zenity.ConfirmPhrase("Delete the production database?", "delete prod",
+		zenity.Executable(path), zenity.Attempts(2))
+	if ok || err != nil {
+		t.Errorf("ConfirmPhrase() = %v, %v", ok, err)
+	}
+	if count, _ := ioutil.ReadFile(path + ".count"); string(count) != "x\nx\n" {
+		t.Errorf("dialog shown %d times, want 2", strings.Count(string(count), "x"))
+	}
+
+	path = stub(t, "zenity", "3.32.0", "exit 1")
+	ok, err = zenity.ConfirmPhrase("Delete the production database?", "delete prod",
+		zenity.Executable(path))
+	if ok || err != nil {
+		t.Errorf("ConfirmPhrase() = %v, %v", ok, err)
+	}
+}
@@ -0,0 +1,5 @@
+package zenity
+
+func entry(text string, options []Option) (string, bool, error) {
+	return "", false, errNoEntry
+}
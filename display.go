@@ -0,0 +1,16 @@
+package zenity
+
+// ErrNoDisplay is returned by dialog functions when there is no graphical
+// session to display dialogs on. The error returned wraps ErrNoDisplay,
+// and describes what is missing.
+const ErrNoDisplay = constError("zenity: no display")
+
+// HasDisplay reports whether there is a graphical session to display
+// dialogs on.
+//
+// On Unix, this checks for an X11 display, a Wayland socket, and, for
+// notifications, a D-Bus session bus. Programs can use it to choose a command
+// line interface up front.
+func HasDisplay() bool {
+	return displayError(nil, false) == nil
+}
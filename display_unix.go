@@ -0,0 +1,58 @@
+// +build !windows,!darwin
+
+package zenity
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// displayError describes why dialogs can't be displayed with env, or with our
+// environment if env is nil. If bus is true, a session bus is also required.
+func displayError(env []string, bus bool) error {
+	if env == nil {
+		env = os.Environ()
+	}
+	getenv := func(key string) string {
+		var val string
+		for _, kv := range env {
+			if strings.HasPrefix(kv, key+"=") {
+				val = kv[len(key)+1:]
+			}
+		}
+		return val
+	}
+	runtime := getenv("XDG_RUNTIME_DIR")
+
+	var missing []string
+	if getenv("DISPLAY") == "" {
+		wayland := getenv("WAYLAND_DISPLAY")
+		if wayland == "" {
+			wayland = "wayland-0"
+		}
+		if !filepath.IsAbs(wayland) && runtime != "" {
+			wayland = filepath.Join(runtime, wayland)
+		}
+		if _, err := os.Stat(wayland); err != nil {
+			missing = append(missing, "DISPLAY is not set, and there is no Wayland socket "+wayland)
+		}
+	}
+	if bus && getenv("DBUS_SESSION_BUS_ADDRESS") == "" {
+		if _, err := os.Stat(filepath.Join(runtime, "bus")); runtime == "" || err != nil {
+			missing = append(missing, "DBUS_SESSION_BUS_ADDRESS is not set, and there is no session bus socket")
+		}
+	}
+
+	if missing != nil {
+		return fmt.Errorf("%w: %s", ErrNoDisplay, strings.Join(missing, "; "))
+	}
+	return nil
+}
+
+// needsBus reports whether the dialog for args is displayed over
+// the session bus, as notifications are.
+func needsBus(args []string) bool {
+	return len(args) > 0 && args[0] == "--notification"
+}
@@ -0,0 +1,45 @@
+package zenity
+
+import "strings"
+
+// Display returns an Option to set the X display, or the Wayland socket,
+// the dialog is displayed on (Unix only).
+//
+// Names with a colon, like ":0" or "host:1.0", are X displays;
+// others, like "wayland-1", are Wayland sockets.
+func Display(name string) Option {
+	return funcOption(func(o *options) { o.display = name })
+}
+
+// Env returns an Option to set an environment variable of the program that
+// displays the dialog (Unix only), e.g. GTK_THEME.
+func Env(key, value string) Option {
+	return funcOption(func(o *options) { o.env = append(o.env, key+"="+value) })
+}
+
+// Locale returns an Option to set the locale of the dialog (Unix only).
+//
+// The locale is a BCP 47 language tag, like "pt-PT",
+// or a POSIX locale name, like "pt_PT.UTF-8".
+// A language alone, like "de", only sets the language of the dialog's text,
+// keeping the locale of the environment.
+func Locale(tag string) Option {
+	return funcOption(func(o *options) { o.locale = tag })
+}
+
+// posixLocale converts a BCP 47 language tag to a POSIX locale name.
+// Any script or variant subtags are dropped. Tags without a territory,
+// like "en", have no matching locale name, and return "".
+func posixLocale(tag string) string {
+	if tag == "C" || tag == "POSIX" || strings.ContainsAny(tag, "_.@") {
+		return tag
+	}
+	parts := strings.Split(tag, "-")
+	name := strings.ToLower(parts[0])
+	for _, p := range parts[1:] {
+		if len(p) == 2 || len(p) == 3 && '0' <= p[0] && p[0] <= '9' {
+			return name + "_" + strings.ToUpper(p) + ".UTF-8"
+		}
+	}
+	return ""
+}
@@ -0,0 +1,23 @@
+package zenity
+
+import "testing"
+
+func TestPosixLocale(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want string
+	}{
+		{"pt-PT", "pt_PT.UTF-8"},
+		{"en", ""},
+		{"zh-Hant", ""},
+		{"zh-Hant-TW", "zh_TW.UTF-8"},
+		{"es-419", "es_419.UTF-8"},
+		{"de_DE.ISO-8859-1", "de_DE.ISO-8859-1"},
+		{"C", "C"},
+	}
+	for _, tt := range tests {
+		if got := posixLocale(tt.tag); got != tt.want {
+			t.Errorf("posixLocale(%q) = %q, want %q", tt.tag, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,6 @@
+// +build !windows,!darwin
+
+package zenity
+
+// VersionAtLeast exports versionAtLeast for tests.
+var VersionAtLeast = versionAtLeast
@@ -9,8 +9,12 @@ import (
 //
 // Returns an empty string on cancel.
 //
-// Valid options: Title, Directory, Filename, ShowHidden, FileFilter(s).
+// Valid options: Title, WindowIcon, Directory, Filename, ShowHidden,
+// FileFilter(s).
 func SelectFile(options ...Option) (string, error) {
+	if err := validate("SelectFile", applyOptions(options)); err != nil {
+		return "", err
+	}
 	return selectFile(options)
 }
 
@@ -18,8 +22,12 @@ func SelectFile(options ...Option) (string, error) {
 //
 // Returns a nil slice on cancel.
 //
-// Valid options: Title, Directory, Filename, ShowHidden, FileFilter(s).
+// Valid options: Title, WindowIcon, Directory, Filename, ShowHidden,
+// FileFilter(s), Separator.
 func SelectFileMutiple(options ...Option) ([]string, error) {
+	if err := validate("SelectFileMutiple", applyOptions(options)); err != nil {
+		return nil, err
+	}
 	return selectFileMutiple(options)
 }
 
@@ -27,9 +35,12 @@ func SelectFileMutiple(options ...Option) ([]string, error) {
 //
 // Returns an empty string on cancel.
 //
-// Valid options: Title, Filename, ConfirmOverwrite, ConfirmCreate, ShowHidden,
-// FileFilter(s).
+// Valid options: Title, WindowIcon, Filename, ConfirmOverwrite, ConfirmCreate,
+// ShowHidden, FileFilter(s).
 func SelectFileSave(options ...Option) (string, error) {
+	if err := validate("SelectFileSave", applyOptions(options)); err != nil {
+		return "", err
+	}
 	return selectFileSave(options)
 }
 
@@ -64,6 +75,14 @@ func ShowHidden() Option {
 	return funcOption(func(o *options) { o.showHidden = true })
 }
 
+// Separator returns an Option to set the separator used to return multiple
+// selected files from the dialog process (Unix and macOS only).
+//
+// The default is a control character that should not appear in file names.
+func Separator(separator string) Option {
+	return funcOption(func(o *options) { o.separator = separator })
+}
+
 // FileFilter is an Option that sets a filename filter.
 //
 // macOS hides filename filters from the user,
@@ -9,6 +9,7 @@ import (
 
 func selectFile(options []Option) (string, error) {
 	opts := applyOptions(options)
+	defer withTimeout(&opts)()
 
 	data := zenutil.File{
 		Prompt:     opts.title,
@@ -37,11 +38,12 @@ func selectFile(options []Option) (string, error) {
 
 func selectFileMutiple(options []Option) ([]string, error) {
 	opts := applyOptions(options)
+	defer withTimeout(&opts)()
 
 	data := zenutil.File{
 		Prompt:     opts.title,
 		Invisibles: opts.showHidden,
-		Separator:  zenutil.Separator,
+		Separator:  opts.separator,
 		Multiple:   true,
 	}
 	if opts.directory {
@@ -51,6 +53,9 @@ func selectFileMutiple(options []Option) ([]string, error) {
 		data.Type = initFilters(opts.fileFilters)
 	}
 	data.Location, _ = splitDirAndName(opts.filename)
+	if data.Separator == "" {
+		data.Separator = zenutil.Separator
+	}
 
 	out, err := zenutil.Run(opts.ctx, "file", data)
 	if err, ok := err.(*exec.ExitError); ok && err.ExitCode() == 1 {
@@ -65,11 +70,12 @@ func selectFileMutiple(options []Option) ([]string, error) {
 	if len(out) == 0 {
 		return nil, nil
 	}
-	return strings.Split(string(out), zenutil.Separator), nil
+	return strings.Split(string(out), data.Separator), nil
 }
 
 func selectFileSave(options []Option) (string, error) {
 	opts := applyOptions(options)
+	defer withTimeout(&opts)()
 
 	data := zenutil.File{
 		Prompt: opts.title,
@@ -9,22 +9,34 @@ import (
 	"github.com/ncruces/zenity/internal/zenutil"
 )
 
-func selectFile(options []Option) (string, error) {
+func selectFile(options []Option) (res string, err error) {
 	opts := applyOptions(options)
+	err = withBackend(&opts, func() error {
+		res, err = selectFileWith(opts)
+		return err
+	})
+	return res, err
+}
+
+func selectFileWith(opts options) (string, error) {
+	cleanup, err := writeIcons(&opts)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
 
 	args := []string{"--file-selection"}
 	if opts.directory {
 		args = append(args, "--directory")
 	}
-	if opts.title != "" {
-		args = append(args, "--title", opts.title)
-	}
+	args = appendGeneral(args, opts)
+	b, _ := primary(opts)
 	if opts.filename != "" {
-		args = append(args, "--filename", opts.filename)
+		args = append(args, "--filename", b.HostPath(opts.filename))
 	}
 	args = append(args, initFilters(opts.fileFilters)...)
 
-	out, err := zenutil.Run(opts.ctx, args)
+	out, err := run(opts, args)
 	if err, ok := err.(*exec.ExitError); ok && err.ExitCode() != 255 {
 		return "", nil
 	}
@@ -34,25 +46,42 @@ func selectFile(options []Option) (string, error) {
 	if len(out) > 0 {
 		out = out[:len(out)-1]
 	}
-	return string(out), nil
+	return b.SandboxPath(string(out)), nil
 }
 
-func selectFileMutiple(options []Option) ([]string, error) {
+func selectFileMutiple(options []Option) (res []string, err error) {
 	opts := applyOptions(options)
+	err = withBackend(&opts, func() error {
+		res, err = selectFileMutipleWith(opts)
+		return err
+	})
+	return res, err
+}
+
+func selectFileMutipleWith(opts options) ([]string, error) {
+	cleanup, err := writeIcons(&opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
 
-	args := []string{"--file-selection", "--multiple", "--separator", zenutil.Separator}
+	separator := opts.separator
+	if separator == "" {
+		separator = zenutil.Separator
+	}
+
+	args := []string{"--file-selection", "--multiple", "--separator", separator}
 	if opts.directory {
 		args = append(args, "--directory")
 	}
-	if opts.title != "" {
-		args = append(args, "--title", opts.title)
-	}
+	args = appendGeneral(args, opts)
+	b, _ := primary(opts)
 	if opts.filename != "" {
-		args = append(args, "--filename", opts.filename)
+		args = append(args, "--filename", b.HostPath(opts.filename))
 	}
 	args = append(args, initFilters(opts.fileFilters)...)
 
-	out, err := zenutil.Run(opts.ctx, args)
+	out, err := run(opts, args)
 	if err, ok := err.(*exec.ExitError); ok && err.ExitCode() != 255 {
 		return nil, nil
 	}
@@ -62,28 +91,45 @@ func selectFileMutiple(options []Option) ([]string, error) {
 	if len(out) > 0 {
 		out = out[:len(out)-1]
 	}
-	return strings.Split(string(out), zenutil.Separator), nil
+	paths := strings.Split(string(out), separator)
+	for i, p := range paths {
+		paths[i] = b.SandboxPath(p)
+	}
+	return paths, nil
 }
 
-func selectFileSave(options []Option) (string, error) {
+func selectFileSave(options []Option) (res string, err error) {
 	opts := applyOptions(options)
+	err = withBackend(&opts, func() error {
+		res, err = selectFileSaveWith(opts)
+		return err
+	})
+	return res, err
+}
+
+func selectFileSaveWith(opts options) (string, error) {
+	cleanup, err := writeIcons(&opts)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
 
 	args := []string{"--file-selection", "--save"}
 	if opts.directory {
 		args = append(args, "--directory")
 	}
-	if opts.title != "" {
-		args = append(args, "--title", opts.title)
-	}
+	args = appendGeneral(args, opts)
+	b, _ := primary(opts)
 	if opts.filename != "" {
-		args = append(args, "--filename", opts.filename)
+		args = append(args, "--filename", b.HostPath(opts.filename))
 	}
-	if opts.confirmOverwrite {
+	if opts.confirmOverwrite && !zenity4(opts) {
+		// zenity 4 always confirms, and deprecated the option.
 		args = append(args, "--confirm-overwrite")
 	}
 	args = append(args, initFilters(opts.fileFilters)...)
 
-	out, err := zenutil.Run(opts.ctx, args)
+	out, err := run(opts, args)
 	if err, ok := err.(*exec.ExitError); ok && err.ExitCode() != 255 {
 		return "", nil
 	}
@@ -93,7 +139,7 @@ func selectFileSave(options []Option) (string, error) {
 	if len(out) > 0 {
 		out = out[:len(out)-1]
 	}
-	return string(out), nil
+	return b.SandboxPath(string(out)), nil
 }
 
 func initFilters(filters []FileFilter) []string {
@@ -19,6 +19,7 @@ var (
 
 func selectFile(options []Option) (string, error) {
 	opts := applyOptions(options)
+	defer withTimeout(&opts)()
 	if opts.directory {
 		res, _, err := pickFolders(opts, false)
 		return res, err
@@ -26,6 +27,7 @@ func selectFile(options []Option) (string, error) {
 
 	var args _OPENFILENAME
 	args.StructSize = uint32(unsafe.Sizeof(args))
+	args.Owner = ownerWindow(opts)
 	args.Flags = 0x81008 // OFN_NOCHANGEDIR|OFN_FILEMUSTEXIST|OFN_EXPLORER
 
 	if opts.title != "" {
@@ -67,6 +69,7 @@ func selectFile(options []Option) (string, error) {
 
 func selectFileMutiple(options []Option) ([]string, error) {
 	opts := applyOptions(options)
+	defer withTimeout(&opts)()
 	if opts.directory {
 		_, res, err := pickFolders(opts, true)
 		return res, err
@@ -74,6 +77,7 @@ func selectFileMutiple(options []Option) ([]string, error) {
 
 	var args _OPENFILENAME
 	args.StructSize = uint32(unsafe.Sizeof(args))
+	args.Owner = ownerWindow(opts)
 	args.Flags = 0x81208 // OFN_NOCHANGEDIR|OFN_ALLOWMULTISELECT|OFN_FILEMUSTEXIST|OFN_EXPLORER
 
 	if opts.title != "" {
@@ -140,6 +144,7 @@ func selectFileMutiple(options []Option) ([]string, error) {
 
 func selectFileSave(options []Option) (string, error) {
 	opts := applyOptions(options)
+	defer withTimeout(&opts)()
 	if opts.directory {
 		res, _, err := pickFolders(opts, false)
 		return res, err
@@ -147,6 +152,7 @@ func selectFileSave(options []Option) (string, error) {
 
 	var args _OPENFILENAME
 	args.StructSize = uint32(unsafe.Sizeof(args))
+	args.Owner = ownerWindow(opts)
 	args.Flags = 0x88808 // OFN_NOCHANGEDIR|OFN_PATHMUSTEXIST|OFN_NOREADONLYRETURN|OFN_EXPLORER
 
 	if opts.title != "" {
@@ -257,7 +263,7 @@ func pickFolders(opts options, multi bool) (str string, lst []string, err error)
 	}
 
 	activate()
-	hr, _, _ = dialog.Call(dialog.vtbl.Show, 0)
+	hr, _, _ = dialog.Call(dialog.vtbl.Show, ownerWindow(opts))
 	if opts.ctx != nil && opts.ctx.Err() != nil {
 		return "", nil, opts.ctx.Err()
 	}
@@ -317,6 +323,7 @@ func pickFolders(opts options, multi bool) (str string, lst []string, err error)
 
 func browseForFolder(opts options) (string, []string, error) {
 	var args _BROWSEINFO
+	args.Owner = ownerWindow(opts)
 	args.Flags = 0x1 // BIF_RETURNONLYFSDIRS
 
 	if opts.title != "" {
@@ -0,0 +1,116 @@
+package zenity
+
+import (
+	"image"
+	"image/png"
+	"io/ioutil"
+	"os"
+)
+
+type iconName string
+type iconFile string
+
+// IconName returns an Option to set the dialog icon to an icon from the icon
+// theme (Unix only).
+func IconName(name string) Option {
+	return funcOption(func(o *options) { o.icon = iconName(name) })
+}
+
+// IconFile returns an Option to set the dialog icon from an image file
+// (Unix and macOS only).
+func IconFile(path string) Option {
+	return funcOption(func(o *options) { o.icon = iconFile(path) })
+}
+
+// IconImage returns an Option to set the dialog icon to an image
+// (Unix and macOS only).
+//
+// The image is written to a temporary PNG file, which is removed once the
+// dialog is dismissed. Notifications load their icon after Notify returns,
+// so theirs are kept for a day, and removed by a later Notify.
+func IconImage(img image.Image) Option {
+	return funcOption(func(o *options) { o.icon = img })
+}
+
+// WindowIcon returns an Option to set the window icon (Unix only).
+//
+// See also WindowIconName, WindowIconFile and WindowIconImage.
+func WindowIcon(icon DialogIcon) Option {
+	return funcOption(func(o *options) {
+		if icon == 0 {
+			o.windowIcon = nil
+		} else {
+			o.windowIcon = icon
+		}
+	})
+}
+
+// WindowIconName returns an Option to set the window icon to an icon from
+// the icon theme (Unix only).
+func WindowIconName(name string) Option {
+	return funcOption(func(o *options) { o.windowIcon = iconName(name) })
+}
+
+// WindowIconFile returns an Option to set the window icon from an image file
+// (Unix only).
+func WindowIconFile(path string) Option {
+	return funcOption(func(o *options) { o.windowIcon = iconFile(path) })
+}
+
+// WindowIconImage returns an Option to set the window icon to an image
+// (Unix only).
+//
+// The image is written to a temporary PNG file, which is removed once the
+// dialog is dismissed.
+func WindowIconImage(img image.Image) Option {
+	return funcOption(func(o *options) { o.windowIcon = img })
+}
+
+// writeIcons writes icons given as images to temporary files, where the
+// program that displays the dialog can read them, and returns a function
+// that removes them. Icon files are translated to paths that program can open.
+func writeIcons(opts *options) (cleanup func(), err error) {
+	return writeIconFiles(opts, "zenity-*.png")
+}
+
+// writeIconFiles is writeIcons, naming the temporary files after pattern.
+func writeIconFiles(opts *options, pattern string) (cleanup func(), err error) {
+	var files []string
+	cleanup = func() {
+		for _, f := range files {
+			os.Remove(f)
+		}
+	}
+
+	for _, icon := range []*interface{}{&opts.icon, &opts.windowIcon} {
+		if file, ok := (*icon).(iconFile); ok {
+			*icon = iconFile(hostPath(*opts, string(file)))
+		}
+		img, ok := (*icon).(image.Image)
+		if !ok {
+			continue
+		}
+
+		file, err := ioutil.TempFile(iconDir(*opts), pattern)
+		if err != nil {
+			cleanup()
+			return nil, err
+		}
+		files = append(files, file.Name())
+
+		// Readable by others, for dialogs that run as another user.
+		err = file.Chmod(0644)
+		if err == nil {
+			err = png.Encode(file, img)
+		}
+		if cerr := file.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			cleanup()
+			return nil, err
+		}
+		*icon = iconFile(file.Name())
+	}
+	return cleanup, nil
+}
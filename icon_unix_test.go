@@ -0,0 +1,146 @@
+// +build !windows,!darwin
+
+package zenity_test
+
+import (
+	"image"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ncruces/zenity"
+)
+
+func TestIconName(t *testing.T) {
+	fakeSession(t)
+	tests := []struct {
+		version string
+		want    []string
+	}{
+		{"3.32.0", []string{"--window-icon=network-error", "--icon-name=network-error"}},
+		{"4.0.1", []string{"--icon=network-error"}},
+	}
+	for _, tt := range tests {
+		path := stub(t, "zenity", tt.version, "")
+
+		_, err := zenity.Error("text", zenity.Executable(path), zenity.IconName("network-error"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		args := stubArgs(t, path)
+		for _, a := range tt.want {
+			if !hasArg(args, a) {
+				t.Errorf("zenity %s: missing %s: %q", tt.version, a, args)
+			}
+		}
+	}
+}
+
+func TestIconImage(t *testing.T) {
+	fakeSession(t)
+	path := stub(t, "zenity", "4.0.1", `
+for arg; do
+	case "$arg" in
+	--icon=*) ls -l "${arg#--icon=}" | grep -q '^-rw-r--r--' || exit 255 ;;
+	esac
+done`)
+
+	img := image.NewRGBA(image.Rect(0, 0, 16, 16))
+	_, err := zenity.Info("text", zenity.Executable(path), zenity.IconImage(img))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, a := range stubArgs(t, path) {
+		if strings.HasPrefix(a, "--icon=") {
+			file := strings.TrimPrefix(a, "--icon=")
+			if !strings.HasSuffix(file, ".png") {
+				t.Errorf("icon is not a PNG: %s", file)
+			}
+			if _, err := os.Stat(file); !os.IsNotExist(err) {
+				t.Errorf("icon was not removed: %s", file)
+			}
+			return
+		}
+	}
+	t.Error("missing --icon")
+}
+
+func TestNotifyIconImage(t *testing.T) {
+	fakeSession(t)
+	setenv(t, "TMPDIR", t.TempDir())
+	path := stub(t, "zenity", "4.0.1", "")
+
+	img := image.NewRGBA(image.Rect(0, 0, 16, 16))
+	err := zenity.Notify("text", zenity.Executable(path), zenity.IconImage(img))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var file string
+	for _, a := range stubArgs(t, path) {
+		if strings.HasPrefix(a, "--icon=") {
+			file = strings.TrimPrefix(a, "--icon=")
+		}
+	}
+	if _, err := os.Stat(file); err != nil {
+		t.Fatalf("icon was removed before it was loaded: %v", err)
+	}
+
+	// Icons are removed by later notifications, once old enough.
+	old := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(file, old, old); err != nil {
+		t.Fatal(err)
+	}
+	if err := zenity.Notify("text", zenity.Executable(path)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(file); !os.IsNotExist(err) {
+		t.Errorf("icon was not removed: %s", file)
+	}
+}
+
+func TestWindowIcon(t *testing.T) {
+	fakeSession(t)
+	path := stub(t, "zenity", "3.32.0", "")
+
+	_, err := zenity.SelectFile(zenity.Executable(path), zenity.WindowIconName("folder"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if args := stubArgs(t, path); !hasArg(args, "--window-icon=folder") {
+		t.Errorf("missing window icon: %q", args)
+	}
+
+	_, err = zenity.Info("text", zenity.Executable(path),
+		zenity.Icon(zenity.InfoIcon), zenity.WindowIconFile(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if args := stubArgs(t, path); !hasArg(args, "--window-icon="+path) || hasArg(args, "--window-icon=info") {
+		t.Errorf("unexpected window icon: %q", args)
+	}
+}
+
+func TestNotifyWindowIcon(t *testing.T) {
+	fakeSession(t)
+	path := stub(t, "zenity", "3.32.0", "")
+
+	err := zenity.Notify("text", zenity.Executable(path),
+		zenity.Icon(zenity.InfoIcon), zenity.WindowIconName("folder"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var icons []string
+	for _, a := range stubArgs(t, path) {
+		if strings.HasPrefix(a, "--window-icon=") {
+			icons = append(icons, a)
+		}
+	}
+	if len(icons) != 1 || icons[0] != "--window-icon=info" {
+		t.Errorf("unexpected window icons: %q", icons)
+	}
+}
@@ -0,0 +1,8 @@
+package zenutil
+
+// Backend is internal.
+type Backend struct {
+	Tool string
+	Path string
+	Host []string // the command that runs Path on the host, from a sandbox
+}
@@ -0,0 +1,29 @@
+package zenutil
+
+import "context"
+
+type commandKey struct{}
+
+// WithCommand is internal.
+//
+// It returns a Context that makes Run replace the current process with the
+// program that displays the dialog, as the zenity command does.
+func WithCommand(ctx context.Context) context.Context {
+	return context.WithValue(ctx, commandKey{}, true)
+}
+
+// IsCommand is internal.
+func IsCommand(ctx context.Context) bool {
+	return ctx != nil && ctx.Value(commandKey{}) != nil
+}
+
+// WithoutCommand is internal.
+//
+// It returns a Context in which Run doesn't replace the current process,
+// for dialogs whose output or exit status must be translated.
+func WithoutCommand(ctx context.Context) context.Context {
+	if !IsCommand(ctx) {
+		return ctx
+	}
+	return context.WithValue(ctx, commandKey{}, nil)
+}
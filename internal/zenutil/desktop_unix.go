@@ -0,0 +1,165 @@
+// +build !windows,!darwin
+
+package zenutil
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// MinimalEnv is internal.
+//
+// It returns the variables of environ needed to reach the display,
+// the session bus, and for the locale. XDG_RUNTIME_DIR holds the Wayland and
+// session bus sockets, when their addresses are relative.
+func MinimalEnv(environ []string) []string {
+	env := []string{}
+	for _, kv := range environ {
+		switch envKey(kv) {
+		case "DISPLAY", "WAYLAND_DISPLAY", "XAUTHORITY", "XDG_RUNTIME_DIR",
+			"DBUS_SESSION_BUS_ADDRESS", "LANG", "LANGUAGE":
+		default:
+			if !strings.HasPrefix(kv, "LC_") {
+				continue
+			}
+		}
+		env = append(env, kv)
+	}
+	return env
+}
+
+func envKey(kv string) string {
+	if i := strings.IndexByte(kv, '='); i >= 0 {
+		return kv[:i]
+	}
+	return ""
+}
+
+// DesktopUser is internal.
+//
+// It finds the user logged in to the desktop, as given by SUDO_UID or
+// PKEXEC_UID, or else the first user with a process in a graphical session,
+// by scanning procfs. It returns the credential of that user, with the
+// supplementary groups of their session, and the environment to reach
+// their display.
+func DesktopUser(procfs string) (*syscall.Credential, []string, error) {
+	want := -1
+	for _, key := range []string{"SUDO_UID", "PKEXEC_UID"} {
+		if val := os.Getenv(key); val != "" {
+			uid, err := strconv.Atoi(val)
+			if err != nil {
+				return nil, nil, fmt.Errorf("zenity: invalid %s: %q", key, val)
+			}
+			want = uid
+			break
+		}
+	}
+
+	dir, err := ioutil.ReadDir(procfs)
+	if err != nil {
+		return nil, nil, err
+	}
+	var pids []int
+	for _, fi := range dir {
+		if pid, err := strconv.Atoi(fi.Name()); err == nil && fi.IsDir() {
+			pids = append(pids, pid)
+		}
+	}
+	sort.Ints(pids)
+
+	for _, pid := range pids {
+		path := filepath.Join(procfs, strconv.Itoa(pid))
+		uid, gid, groups, err := procIDs(filepath.Join(path, "status"))
+		if err != nil || uid == 0 || want >= 0 && uid != want {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(path, "environ"))
+		if err != nil {
+			continue
+		}
+		environ := strings.Split(string(bytes.TrimSuffix(data, []byte{0})), "\x00")
+		if !graphical(environ) {
+			continue
+		}
+
+		env := MinimalEnv(environ)
+		for _, kv := range environ {
+			switch envKey(kv) {
+			case "HOME", "USER", "LOGNAME":
+				env = append(env, kv)
+			}
+		}
+		return &syscall.Credential{Uid: uint32(uid), Gid: uint32(gid), Groups: groups}, env, nil
+	}
+
+	if want >= 0 {
+		return nil, nil, fmt.Errorf("zenity: no graphical session found for user %d", want)
+	}
+	return nil, nil, fmt.Errorf("zenity: no graphical session found")
+}
+
+// procIDs reads the real user and group IDs, and the supplementary groups,
+// of a process from its status file.
+func procIDs(status string) (uid, gid int, groups []uint32, err error) {
+	f, err := os.Open(status)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	defer f.Close()
+
+	uid, gid = -1, -1
+	groups = []uint32{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 1 {
+			continue
+		}
+		switch fields[0] {
+		case "Uid:", "Gid:":
+			if len(fields) < 2 {
+				err = fmt.Errorf("zenity: invalid %s", status)
+			} else if fields[0] == "Uid:" {
+				uid, err = strconv.Atoi(fields[1])
+			} else {
+				gid, err = strconv.Atoi(fields[1])
+			}
+		case "Groups:":
+			for _, f := range fields[1:] {
+				var g uint64
+				if g, err = strconv.ParseUint(f, 10, 32); err != nil {
+					break
+				}
+				groups = append(groups, uint32(g))
+			}
+		}
+		if err != nil {
+			return 0, 0, nil, err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, 0, nil, err
+	}
+	if uid < 0 || gid < 0 {
+		return 0, 0, nil, fmt.Errorf("zenity: no ids in %s", status)
+	}
+	return uid, gid, groups, nil
+}
+
+func graphical(environ []string) bool {
+	for _, kv := range environ {
+		switch envKey(kv) {
+		case "DISPLAY", "WAYLAND_DISPLAY":
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+// +build !windows,!darwin
+
+package zenutil
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// fakeProcfs writes a procfs tree with a process for each pid,
+// given its user ID and environment.
+func fakeProcfs(t *testing.T, procs map[int]struct {
+	uid     int
+	environ []string
+}) string {
+	root := t.TempDir()
+	for pid, p := range procs {
+		dir := filepath.Join(root, strconv.Itoa(pid))
+		if err := os.Mkdir(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		status := "Name:\tproc\nUid:\t" + strconv.Itoa(p.uid) + "\t" + strconv.Itoa(p.uid) + "\t0\t0\n" +
+			"Gid:\t" + strconv.Itoa(p.uid+1) + "\t0\t0\t0\n" +
+			"Groups:\t" + strconv.Itoa(p.uid+1) + " 27 44 \n"
+		environ := strings.Join(p.environ, "\x00") + "\x00"
+		if err := ioutil.WriteFile(filepath.Join(dir, "status"), []byte(status), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, "environ"), []byte(environ), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(root, "self"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func TestDesktopUser(t *testing.T) {
+	procfs := fakeProcfs(t, map[int]struct {
+		uid     int
+		environ []string
+	}{
+		1:    {0, []string{"DISPLAY=:9"}},
+		200:  {1000, []string{"HOME=/home/alice"}},
+		1000: {1001, []string{"WAYLAND_DISPLAY=wayland-0", "HOME=/home/bob"}},
+		300:  {1000, []string{"DISPLAY=:0", "XAUTHORITY=/home/alice/.Xauthority", "SECRET=x", "HOME=/home/alice", "LC_ALL=pt_PT.UTF-8"}},
+	})
+
+	defer os.Setenv("SUDO_UID", os.Getenv("SUDO_UID"))
+	defer os.Setenv("PKEXEC_UID", os.Getenv("PKEXEC_UID"))
+
+	tests := []struct {
+		sudo, pkexec string
+		uid          uint32
+		env          []string
+		err          bool
+	}{
+		{uid: 1000, env: []string{"DISPLAY=:0", "XAUTHORITY=/home/alice/.Xauthority", "LC_ALL=pt_PT.UTF-8", "HOME=/home/alice"}},
+		{sudo: "1001", uid: 1001, env: []string{"WAYLAND_DISPLAY=wayland-0", "HOME=/home/bob"}},
+		{pkexec: "1001", uid: 1001, env: []string{"WAYLAND_DISPLAY=wayland-0", "HOME=/home/bob"}},
+		{sudo: "1002", err: true},
+		{sudo: "alice", err: true},
+	}
+	for _, tt := range tests {
+		os.Setenv("SUDO_UID", tt.sudo)
+		os.Setenv("PKEXEC_UID", tt.pkexec)
+
+		cred, env, err := DesktopUser(procfs)
+		if tt.err {
+			if err == nil {
+				t.Errorf("DesktopUser(%q, %q) = %v, want error", tt.sudo, tt.pkexec, cred)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("DesktopUser(%q, %q) = %v", tt.sudo, tt.pkexec, err)
+			continue
+		}
+		if cred.Uid != tt.uid || cred.Gid != tt.uid+1 ||
+			!reflect.DeepEqual(cred.Groups, []uint32{tt.uid + 1, 27, 44}) {
+			t.Errorf("DesktopUser(%q, %q) = %v", tt.sudo, tt.pkexec, cred)
+		}
+		if !reflect.DeepEqual(env, tt.env) {
+			t.Errorf("DesktopUser(%q, %q) env = %q, want %q", tt.sudo, tt.pkexec, env, tt.env)
+		}
+	}
+}
+
+func TestMinimalEnv(t *testing.T) {
+	env := MinimalEnv([]string{"PATH=/bin", "DISPLAY=:0", "LC_TIME=C", "DBUS_SESSION_BUS_ADDRESS=unix:path=/run/bus", "TOKEN=x"})
+	want := []string{"DISPLAY=:0", "LC_TIME=C", "DBUS_SESSION_BUS_ADDRESS=unix:path=/run/bus"}
+	if !reflect.DeepEqual(env, want) {
+		t.Errorf("MinimalEnv() = %q, want %q", env, want)
+	}
+}
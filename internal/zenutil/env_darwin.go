@@ -3,11 +3,5 @@ package zenutil
 // These are internal.
 const (
 	LineBreak = "\n"
-)
-
-// These are internal.
-var (
-	Command   bool
-	Timeout   int
 	Separator = "\x00"
 )
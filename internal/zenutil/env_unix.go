@@ -6,11 +6,5 @@ package zenutil
 // These are internal.
 const (
 	LineBreak = "\n"
-)
-
-// These are internal.
-var (
-	Command   bool
-	Timeout   int
 	Separator = "\x1e"
 )
@@ -3,11 +3,5 @@ package zenutil
 // These are internal.
 const (
 	LineBreak = "\r\n"
-)
-
-// These are internal.
-var (
-	Command   bool
-	Timeout   int
-	Separator string
+	Separator = ""
 )
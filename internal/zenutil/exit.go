@@ -0,0 +1,28 @@
+// +build !windows
+
+package zenutil
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+)
+
+// timeoutError converts the exit status used by dialogs that time out.
+func timeoutError(err error) error {
+	if err, ok := err.(*exec.ExitError); ok && err.ExitCode() == 5 {
+		return context.DeadlineExceeded
+	}
+	return err
+}
+
+// LaunchFailed is internal.
+//
+// It reports whether err means the dialog program could not be found or
+// started. Once started, a program may have displayed the dialog, whatever
+// its exit status, so it is not considered to have failed to launch.
+func LaunchFailed(err error) bool {
+	var execErr *exec.Error
+	return errors.As(err, &execErr) || errors.Is(err, os.ErrNotExist)
+}
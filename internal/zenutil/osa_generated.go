@@ -17,6 +17,33 @@ set c to choose color default color { {{index . 0}},{{index . 1}},{{index . 2}}
 "rgb(" & (item 1 of c) div 256 & "," & (item 2 of c) div 256 & "," & (item 3 of c) div 256 & ")"
 end tell
 {{- end}}
+{{define "entry" -}}
+var app = Application.currentApplication()
+app.includeStandardAdditions = true
+app.activate()
+var opts = {defaultAnswer: ""}
+{{if .Title -}}
+opts.withTitle = {{json .Title}}
+{{end -}}
+{{if .Buttons -}}
+opts.buttons = {{json .Buttons}}
+{{end -}}
+{{if .Cancel -}}
+opts.cancelButton = {{json .Cancel}}
+{{end -}}
+{{if .Default -}}
+opts.defaultButton = {{json .Default}}
+{{end -}}
+{{if .Timeout -}}
+opts.givingUpAfter = {{json .Timeout}}
+{{end -}}
+var res = app.displayDialog({{json .Text}}, opts)
+if (res.gaveUp) {
+ObjC.import("stdlib")
+$.exit(5)
+}
+res.textReturned
+{{- end}}
 {{define "file" -}}
 var app = Application.currentApplication()
 app.includeStandardAdditions = true
@@ -64,6 +91,9 @@ opts.withTitle = {{json .Title}}
 {{if .Icon -}}
 opts.withIcon = {{json .Icon}}
 {{end -}}
+{{if .IconPath -}}
+opts.withIcon = Path({{json .IconPath}})
+{{end -}}
 {{if .Buttons -}}
 opts.buttons = {{json .Buttons}}
 {{end -}}
@@ -23,7 +23,7 @@ func Run(ctx context.Context, script string, data interface{}) ([]byte, error) {
 		lang = "JavaScript"
 	}
 
-	if Command {
+	if IsCommand(ctx) {
 		path, err := exec.LookPath("osascript")
 		if err == nil {
 			os.Stderr.Close()
@@ -38,11 +38,12 @@ func Run(ctx context.Context, script string, data interface{}) ([]byte, error) {
 		if ctx.Err() != nil {
 			err = ctx.Err()
 		}
-		return out, err
+		return out, timeoutError(err)
 	}
 	cmd := exec.Command("osascript", "-l", lang)
 	cmd.Stdin = strings.NewReader(script)
-	return cmd.Output()
+	out, err := cmd.Output()
+	return out, timeoutError(err)
 }
 
 type File struct {
@@ -63,6 +64,7 @@ type Msg struct {
 	As        string
 	Title     string
 	Icon      string
+	IconPath  string
 	Extra     string
 	Buttons   []string
 	Cancel    int
@@ -70,6 +72,15 @@ type Msg struct {
 	Timeout   int
 }
 
+type Entry struct {
+	Text    string
+	Title   string
+	Buttons []string
+	Cancel  int
+	Default int
+	Timeout int
+}
+
 type Notify struct {
 	Text     string
 	Title    string
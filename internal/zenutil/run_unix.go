@@ -3,40 +3,337 @@
 package zenutil
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"io"
 	"os"
 	"os/exec"
-	"strconv"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
 	"syscall"
+	"time"
 )
 
-var tool, path string
+var tools = [...]string{"qarma", "zenity", "matedialog"}
 
-func init() {
-	for _, tool = range [3]string{"qarma", "zenity", "matedialog"} {
-		path, _ = exec.LookPath(tool)
-		if path != "" {
-			return
+var lookup struct {
+	sync.Mutex
+	env      string
+	backends []Backend
+	versions map[string]string
+}
+
+// Backends is internal.
+func Backends() []Backend {
+	env := os.Getenv("ZENITY_EXECUTABLE") + "\x00" + os.Getenv("PATH")
+
+	lookup.Lock()
+	if lookup.backends != nil && lookup.env == env {
+		defer lookup.Unlock()
+		return lookup.backends
+	}
+	lookup.Unlock()
+
+	res := []Backend{}
+	seen := map[string]bool{}
+	add := func(tool, path string) {
+		real, err := filepath.EvalSymlinks(path)
+		if err != nil {
+			real = path
+		}
+		if !seen[real] {
+			seen[real] = true
+			res = append(res, Backend{Tool: tool, Path: path})
+		}
+	}
+	if exe := os.Getenv("ZENITY_EXECUTABLE"); exe != "" {
+		if path, err := exec.LookPath(exe); err == nil {
+			add(toolName(path), path)
+		}
+	}
+	for _, tool := range tools {
+		if path, err := exec.LookPath(tool); err == nil {
+			add(tool, path)
+		}
+	}
+	var versions map[string]string
+	if len(res) == 0 {
+		if host := hostLauncher(); host != nil {
+			res, versions = hostBackends(host)
+		}
+	}
+
+	lookup.Lock()
+	defer lookup.Unlock()
+	for key, v := range versions {
+		if lookup.versions == nil {
+			lookup.versions = map[string]string{}
+		}
+		lookup.versions[key] = v
+	}
+	lookup.env = env
+	lookup.backends = res
+	return res
+}
+
+// Lookup is internal.
+func Lookup(executable string) (Backend, error) {
+	if executable != "" {
+		path, err := exec.LookPath(executable)
+		if err != nil {
+			return Backend{}, err
 		}
+		return Backend{Tool: toolName(path), Path: path}, nil
 	}
-	tool = "zenity"
+	if found := Backends(); len(found) > 0 {
+		return found[0], nil
+	}
+	return Backend{}, &exec.Error{Name: "zenity", Err: exec.ErrNotFound}
+}
+
+// Compatible is internal.
+//
+// It reports whether the program of b accepts zenity's arguments.
+func (b Backend) Compatible() bool {
+	for _, tool := range tools {
+		if b.Tool == tool {
+			return true
+		}
+	}
+	return false
+}
+
+var versionRegexp = regexp.MustCompile(`\d+(\.\d+)*`)
+
+// versionTimeout bounds how long a program may take to report its version.
+var versionTimeout = 5 * time.Second
+
+// Version is internal.
+func (b Backend) Version() string {
+	key := b.key()
+	lookup.Lock()
+	v, ok := lookup.versions[key]
+	lookup.Unlock()
+	if ok {
+		return v
+	}
+
+	// Probe without holding the lock, so that a program that hangs
+	// doesn't block dialogs displayed by other programs.
+	ctx, cancel := context.WithTimeout(context.Background(), versionTimeout)
+	defer cancel()
+	name, args := b.command([]string{"--version"}, nil)
+	out, _ := exec.CommandContext(ctx, name, args...).Output()
+	v = versionRegexp.FindString(string(out))
+
+	lookup.Lock()
+	defer lookup.Unlock()
+	if lookup.versions == nil {
+		lookup.versions = map[string]string{}
+	}
+	lookup.versions[key] = v
+	return v
+}
+
+// key identifies b in the version cache.
+func (b Backend) key() string {
+	return strings.Join(append(b.Host[:len(b.Host):len(b.Host)], b.Path), "\x00")
+}
+
+// command returns the program and arguments that run b with args.
+// Programs on the host don't inherit our environment, so the variables env
+// sets differently from ours are passed to them explicitly; others, which
+// may be secret, or only make sense in the sandbox, are not.
+func (b Backend) command(args, env []string) (string, []string) {
+	if b.Host == nil {
+		return b.Path, args
+	}
+	argv := b.Host[1:len(b.Host):len(b.Host)]
+	if changed := changedEnv(env); changed != nil {
+		if toolName(b.Host[0]) == "flatpak-spawn" {
+			for _, kv := range changed {
+				argv = append(argv, "--env="+kv)
+			}
+		} else {
+			argv = append(append(argv, "env"), changed...)
+		}
+	}
+	argv = append(argv, b.Path)
+	return b.Host[0], append(argv, args...)
+}
+
+func toolName(path string) string {
+	name := filepath.Base(path)
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+// Options is internal.
+type Options struct {
+	Backend    Backend // if set, Executable is ignored
+	Executable string
+	Stdin      io.Reader
+	Env        []string
+	Credential *syscall.Credential
 }
 
 // Run is internal.
-func Run(ctx context.Context, args []string) ([]byte, error) {
-	if Command && path != "" {
-		if Timeout > 0 {
-			args = append(args, "--timeout", strconv.Itoa(Timeout))
+func Run(ctx context.Context, args []string, opts Options) ([]byte, error) {
+	if ctx != nil && ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	b := opts.Backend
+	if b.Path == "" {
+		var err error
+		if b, err = Lookup(opts.Executable); err != nil {
+			return nil, err
 		}
-		syscall.Exec(path, append([]string{tool}, args...), os.Environ())
 	}
+	name, argv := b.command(args, opts.Env)
 
-	if ctx != nil {
-		out, err := exec.CommandContext(ctx, tool, args...).Output()
-		if ctx.Err() != nil {
-			err = ctx.Err()
+	env := opts.Env
+	if env == nil {
+		env = os.Environ()
+	}
+
+	if IsCommand(ctx) && opts.Stdin == nil && opts.Credential == nil {
+		arg0 := b.Tool
+		if b.Host != nil {
+			arg0 = filepath.Base(name)
 		}
-		return out, err
+		syscall.Exec(name, append([]string{arg0}, argv...), env)
+	}
+
+	var out bytes.Buffer
+	cmd := exec.Command(name, argv...)
+	cmd.Stdin = opts.Stdin
+	cmd.Stdout = &out
+	cmd.Env = env
+	// Start the dialog in its own process group, so that wrapper scripts
+	// don't leave orphaned dialogs on screen when it is canceled.
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Setpgid:    true,
+		Credential: opts.Credential,
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	defer watch(cmd.Process.Pid)()
+
+	if ctx == nil {
+		err := cmd.Wait()
+		return out.Bytes(), timeoutError(err)
+	}
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		select {
+		case <-done:
+		case <-ctx.Done():
+			terminate(cmd.Process.Pid, done)
+		}
+	}()
+
+	err := cmd.Wait()
+	close(done)
+	<-stopped
+	if ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	return out.Bytes(), timeoutError(err)
+}
+
+// The watchdog is a process that terminates the process groups of the dialogs
+// still showing when we exit, however we do. It reads lines that add (+) and
+// remove (-) groups, and terminates those left when its input is closed.
+//
+// Dialogs are not in our process group, so they don't get the signals
+// the terminal sends us, e.g. on Ctrl-C. A signal handler to forward them
+// would change how the program handles those signals.
+const watchdogScript = `
+groups=
+while read op pgid; do
+	case "$op" in
+	+) groups="$groups $pgid" ;;
+	-) left=
+	   for g in $groups; do [ "$g" = "$pgid" ] || left="$left $g"; done
+	   groups=$left ;;
+	esac
+done
+for g in $groups; do kill -s TERM -- "-$g"; done 2> /dev/null
+`
+
+var watchdog struct {
+	sync.Mutex
+	cmd *exec.Cmd
+	w   *os.File
+}
+
+// watch has the watchdog, started once, terminate process group pgid if we
+// exit while it runs, and returns a function that stops watching it.
+func watch(pgid int) (unwatch func()) {
+	watchdog.Lock()
+	defer watchdog.Unlock()
+
+	for retry := 0; retry < 2; retry++ {
+		if watchdog.w == nil && startWatchdog() != nil {
+			break
+		}
+		w := watchdog.w
+		if _, err := fmt.Fprintf(w, "+ %d\n", pgid); err != nil {
+			// The watchdog died; reap it, and start another.
+			w.Close()
+			watchdog.cmd.Wait()
+			watchdog.cmd, watchdog.w = nil, nil
+			continue
+		}
+		return func() {
+			watchdog.Lock()
+			defer watchdog.Unlock()
+			if watchdog.w == w {
+				fmt.Fprintf(w, "- %d\n", pgid)
+			}
+		}
+	}
+	return func() {}
+}
+
+func startWatchdog() error {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	cmd := exec.Command("/bin/sh", "-c", watchdogScript)
+	cmd.Stdin = r
+	// In its own process group, so it outlives signals the terminal sends us.
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err := cmd.Start(); err != nil {
+		w.Close()
+		return err
+	}
+	watchdog.cmd, watchdog.w = cmd, w
+	return nil
+}
+
+var gracePeriod = 2 * time.Second
+
+// terminate sends SIGTERM to process group pgid and, unless its leader exits
+// (done is closed) within the grace period, SIGKILL. Any processes left in the
+// group are killed as well.
+func terminate(pgid int, done <-chan struct{}) {
+	syscall.Kill(-pgid, syscall.SIGTERM)
+	timer := time.NewTimer(gracePeriod)
+	defer timer.Stop()
+	select {
+	case <-done:
+	case <-timer.C:
 	}
-	return exec.Command(tool, args...).Output()
+	syscall.Kill(-pgid, syscall.SIGKILL)
 }
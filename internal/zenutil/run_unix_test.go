@@ -0,0 +1,257 @@
+// +build !windows,!darwin
+
+package zenutil
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// script writes a shell script that starts a background child, saves its pid,
+// and waits for it.
+func script(t *testing.T, body string) string {
+	path := filepath.Join(t.TempDir(), "zenity")
+	err := ioutil.WriteFile(path, []byte(`#!/bin/sh
+`+body+`
+sleep 60 &
+echo $! > "$0.child"
+wait
+`), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func childPid(t *testing.T, path string) int {
+	out, err := ioutil.ReadFile(path + ".child")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(out)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pid
+}
+
+// alive reports whether process pid is running, and not a zombie.
+func alive(pid int) bool {
+	stat, err := ioutil.ReadFile("/proc/" + strconv.Itoa(pid) + "/stat")
+	if os.IsNotExist(err) {
+		return false
+	}
+	if err != nil {
+		return syscall.Kill(pid, 0) == nil
+	}
+	i := bytes.LastIndexByte(stat, ')')
+	return i < 0 || i+2 >= len(stat) || stat[i+2] != 'Z'
+}
+
+func waitDead(t *testing.T, pid int) {
+	for i := 0; i < 100; i++ {
+		if !alive(pid) {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	syscall.Kill(pid, syscall.SIGKILL)
+	t.Errorf("process %d left running", pid)
+}
+
+func TestRunTerminate(t *testing.T) {
+	path := script(t, "")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := Run(ctx, nil, Options{Executable: path})
+	if err != context.DeadlineExceeded {
+		t.Errorf("Run() = %v, want DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed >= gracePeriod {
+		t.Errorf("Run() took %v, want less than the grace period", elapsed)
+	}
+	waitDead(t, childPid(t, path))
+}
+
+func TestRunKill(t *testing.T) {
+	defer func(grace time.Duration) { gracePeriod = grace }(gracePeriod)
+	gracePeriod = 300 * time.Millisecond
+
+	path := script(t, "trap '' TERM")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(200*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := Run(ctx, nil, Options{Executable: path})
+	if err != context.Canceled {
+		t.Errorf("Run() = %v, want Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed < gracePeriod {
+		t.Errorf("Run() took %v, want at least the grace period", elapsed)
+	}
+	waitDead(t, childPid(t, path))
+}
+
+func TestRunLeavesOthers(t *testing.T) {
+	// The dialog opened something, e.g. a browser, that outlives it.
+	path := filepath.Join(t.TempDir(), "zenity")
+	err := ioutil.WriteFile(path, []byte(`#!/bin/sh
+sleep 60 > /dev/null &
+echo $! > "$0.child"
+`), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := Run(context.Background(), nil, Options{Executable: path}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pid := childPid(t, path)
+	time.Sleep(50 * time.Millisecond)
+	if !alive(pid) {
+		t.Error("process started by the dialog was terminated")
+	}
+	syscall.Kill(pid, syscall.SIGKILL)
+}
+
+func TestRunOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "zenity")
+	err := ioutil.WriteFile(path, []byte("#!/bin/sh\ncat\nexit 5\n"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := Run(context.Background(), nil, Options{
+		Executable: path,
+		Stdin:      strings.NewReader("text"),
+	})
+	if string(out) != "text" || err != context.DeadlineExceeded {
+		t.Errorf("Run() = %q, %v", out, err)
+	}
+}
+
+func TestLaunchFailed(t *testing.T) {
+	tests := []struct {
+		script string
+		want   bool
+	}{
+		{"exit 0", false},
+		{"exit 1", false},
+		{"exit 5", false},
+		{"exit 126", false},
+		{"exit 127", false},
+		{"exit 255", false},
+		{"kill -SEGV $$", false},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "zenity")
+		err := ioutil.WriteFile(path, []byte("#!/bin/sh\n"+tt.script+"\n"), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = Run(nil, nil, Options{Executable: path})
+		if got := LaunchFailed(err); got != tt.want {
+			t.Errorf("LaunchFailed(%q) = %v, want %v", err, got, tt.want)
+		}
+	}
+
+	_, err := Run(nil, nil, Options{Executable: "/nonexistent/zenity"})
+	if !LaunchFailed(err) {
+		t.Errorf("LaunchFailed(%q) = false, want true", err)
+	}
+
+	// A script with a missing interpreter is found, but can't be started.
+	path := filepath.Join(t.TempDir(), "zenity")
+	if err := ioutil.WriteFile(path, []byte("#!/nonexistent/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	_, err = Run(nil, nil, Options{Executable: path})
+	if !LaunchFailed(err) {
+		t.Errorf("LaunchFailed(%q) = false, want true", err)
+	}
+}
+
+func TestBackendsDuplicate(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "zenity")
+	if err := ioutil.WriteFile(path, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(t.TempDir(), "dialog")
+	if err := os.Symlink(path, link); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"PATH", "ZENITY_EXECUTABLE"} {
+		defer os.Setenv(key, os.Getenv(key))
+	}
+	os.Setenv("PATH", dir)
+	os.Setenv("ZENITY_EXECUTABLE", link)
+
+	backends := Backends()
+	if len(backends) != 1 || backends[0].Path != link {
+		t.Errorf("Backends() = %v, want only %s", backends, link)
+	}
+}
+
+func TestVersionTimeout(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "zenity")
+	if err := ioutil.WriteFile(path, []byte("#!/bin/sh\nexec sleep 60\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func(old time.Duration) { versionTimeout = old }(versionTimeout)
+	versionTimeout = 100 * time.Millisecond
+
+	start := time.Now()
+	if v := (Backend{Tool: "zenity", Path: path}).Version(); v != "" {
+		t.Errorf("Version() = %q", v)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Version() took %v", elapsed)
+	}
+}
+
+func TestRunParentSignaled(t *testing.T) {
+	if path := os.Getenv("ZENUTIL_TEST_DIALOG"); path != "" {
+		// The parent process, shows a dialog until signaled.
+		Run(context.Background(), nil, Options{Executable: path})
+		return
+	}
+
+	path := script(t, "")
+	parent := exec.Command(os.Args[0], "-test.run=^TestRunParentSignaled$")
+	parent.Env = append(os.Environ(), "ZENUTIL_TEST_DIALOG="+path)
+	if err := parent.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 500; i++ {
+		if _, err := os.Stat(path + ".child"); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	parent.Process.Signal(os.Interrupt)
+	if err := parent.Wait(); err == nil {
+		t.Error("parent survived the signal")
+	}
+	waitDead(t, childPid(t, path))
+}
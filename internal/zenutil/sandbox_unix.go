@@ -0,0 +1,135 @@
+// +build !windows,!darwin
+
+package zenutil
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	flatpakInfo = "/.flatpak-info"
+	hostRoot    = "/run/host"
+)
+
+// hostLauncher returns the command that runs programs on the host, from
+// inside a Flatpak sandbox, or a toolbox or distrobox container, or nil.
+//
+// Snap confinement offers no way to run programs on the host.
+func hostLauncher() []string {
+	if _, err := os.Stat(flatpakInfo); err == nil {
+		if path, err := exec.LookPath("flatpak-spawn"); err == nil {
+			return []string{path, "--host"}
+		}
+		return nil
+	}
+	if os.Getenv("container") != "" && os.Getenv("SNAP") == "" {
+		if path, err := exec.LookPath("distrobox-host-exec"); err == nil {
+			return []string{path}
+		}
+		// Toolbox containers ship flatpak-spawn.
+		if path, err := exec.LookPath("flatpak-spawn"); err == nil {
+			return []string{path, "--host"}
+		}
+	}
+	return nil
+}
+
+// hostBackends finds the programs that can display dialogs on the host,
+// through host, and their versions, keyed for the version cache.
+func hostBackends(host []string) ([]Backend, map[string]string) {
+	res := []Backend{}
+	versions := map[string]string{}
+	for _, tool := range tools {
+		b := Backend{Tool: tool, Path: tool, Host: host}
+		ctx, cancel := context.WithTimeout(context.Background(), versionTimeout)
+		name, args := b.command([]string{"--version"}, nil)
+		out, err := exec.CommandContext(ctx, name, args...).Output()
+		cancel()
+		if err != nil {
+			continue
+		}
+		versions[b.key()] = versionRegexp.FindString(string(out))
+		res = append(res, b)
+	}
+	return res, versions
+}
+
+// changedEnv returns the variables of env that are not set, or set to
+// a different value, in our environment.
+func changedEnv(env []string) []string {
+	last := map[string]int{}
+	for i, kv := range env {
+		last[envKey(kv)] = i
+	}
+	var res []string
+	for i, kv := range env {
+		key := envKey(kv)
+		if last[key] != i {
+			continue
+		}
+		if val, ok := os.LookupEnv(key); ok && key+"="+val == kv {
+			continue
+		}
+		res = append(res, kv)
+	}
+	return res
+}
+
+// HostPath is internal.
+//
+// It translates a path in the sandbox to one the host program can open.
+func (b Backend) HostPath(path string) string {
+	if b.Host != nil && strings.HasPrefix(path, hostRoot+"/") {
+		return strings.TrimPrefix(path, hostRoot)
+	}
+	return path
+}
+
+// TempDir is internal.
+//
+// It returns the directory for temporary files the program must read, or ""
+// for the default. The /tmp of a Flatpak sandbox is private, but the runtime
+// directory of the app is shared with the host, at the same path.
+func (b Backend) TempDir() string {
+	if b.Host == nil {
+		return ""
+	}
+	id, runtime := os.Getenv("FLATPAK_ID"), os.Getenv("XDG_RUNTIME_DIR")
+	if id == "" || runtime == "" {
+		return ""
+	}
+	dir := filepath.Join(runtime, "app", id)
+	if fi, err := os.Stat(dir); err != nil || !fi.IsDir() {
+		return ""
+	}
+	return dir
+}
+
+// SandboxPath is internal.
+//
+// It translates a path returned by the host program to one visible in the
+// sandbox: paths shared with the host are kept, other host files are found
+// under /run/host, if mounted. Files to be saved need only their directory.
+func (b Backend) SandboxPath(path string) string {
+	if b.Host == nil || path == "" {
+		return path
+	}
+	exists := func(path string) bool {
+		_, err := os.Stat(path)
+		if os.IsNotExist(err) {
+			_, err = os.Stat(filepath.Dir(path))
+		}
+		return err == nil
+	}
+	if exists(path) {
+		return path
+	}
+	if host := filepath.Join(hostRoot, path); exists(host) {
+		return host
+	}
+	return path
+}
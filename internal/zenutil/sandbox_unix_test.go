@@ -0,0 +1,189 @@
+// +build !windows,!darwin
+
+package zenutil
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fakeSandbox makes the test look like it runs in a Flatpak sandbox, with
+// a flatpak-spawn that runs a zenity stub on the host, and no zenity.
+func fakeSandbox(t *testing.T) string {
+	dir := t.TempDir()
+	spawn := filepath.Join(dir, "flatpak-spawn")
+	err := ioutil.WriteFile(spawn, []byte(`#!/bin/sh
+[ "$1" = --host ] || exit 1
+shift
+: > "$0.env"
+while [ "${1#--env=}" != "$1" ]; do
+	echo "${1#--env=}" >> "$0.env"
+	shift
+done
+[ "$1" = zenity ] || exit 127
+shift
+if [ "$1" = --version ]; then
+	echo 3.44.0
+	exit 0
+fi
+printf '%s\n' "$@" > "$0.args"
+`), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info := filepath.Join(dir, ".flatpak-info")
+	if err := ioutil.WriteFile(info, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	old := flatpakInfo
+	flatpakInfo = info
+	t.Cleanup(func() { flatpakInfo = old })
+
+	for _, key := range []string{"PATH", "ZENITY_EXECUTABLE"} {
+		key, val := key, os.Getenv(key)
+		t.Cleanup(func() { os.Setenv(key, val) })
+	}
+	os.Setenv("PATH", dir)
+	os.Unsetenv("ZENITY_EXECUTABLE")
+	return spawn
+}
+
+func TestHostBackends(t *testing.T) {
+	spawn := fakeSandbox(t)
+
+	backends := Backends()
+	want := []Backend{{Tool: "zenity", Path: "zenity", Host: []string{spawn, "--host"}}}
+	if !reflect.DeepEqual(backends, want) {
+		t.Fatalf("Backends() = %v, want %v", backends, want)
+	}
+	if v := backends[0].Version(); v != "3.44.0" {
+		t.Errorf("Version() = %q", v)
+	}
+
+	_, err := Run(nil, []string{"--info", "--text", "text"}, Options{Backend: backends[0]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	args, err := ioutil.ReadFile(spawn + ".args")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(args) != "--info\n--text\ntext\n" {
+		t.Errorf("unexpected arguments: %q", args)
+	}
+
+	// The host program doesn't inherit our environment,
+	// but only what changes in it is passed.
+	_, err = Run(nil, []string{"--info"}, Options{
+		Backend: backends[0],
+		Env:     append(os.Environ(), "DISPLAY=:1", "LANG=C", "LANG=pt_PT.UTF-8"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := ioutil.ReadFile(spawn + ".env")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(env) != "DISPLAY=:1\nLANG=pt_PT.UTF-8\n" {
+		t.Errorf("unexpected environment: %q", env)
+	}
+}
+
+func TestTempDir(t *testing.T) {
+	runtime := t.TempDir()
+	dir := filepath.Join(runtime, "app", "org.example.App")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"FLATPAK_ID", "XDG_RUNTIME_DIR"} {
+		defer os.Setenv(key, os.Getenv(key))
+	}
+	os.Setenv("FLATPAK_ID", "org.example.App")
+	os.Setenv("XDG_RUNTIME_DIR", runtime)
+
+	host := Backend{Tool: "zenity", Path: "zenity", Host: []string{"flatpak-spawn", "--host"}}
+	if got := host.TempDir(); got != dir {
+		t.Errorf("TempDir() = %q, want %q", got, dir)
+	}
+	if got := (Backend{Path: "/usr/bin/zenity"}).TempDir(); got != "" {
+		t.Errorf("TempDir() = %q outside a sandbox", got)
+	}
+}
+
+func TestHostLauncher(t *testing.T) {
+	dir := t.TempDir()
+	launcher := filepath.Join(dir, "distrobox-host-exec")
+	if err := ioutil.WriteFile(launcher, nil, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"PATH", "container", "SNAP"} {
+		defer os.Setenv(key, os.Getenv(key))
+	}
+	os.Setenv("PATH", dir)
+	os.Setenv("container", "podman")
+	os.Unsetenv("SNAP")
+
+	if got := hostLauncher(); !reflect.DeepEqual(got, []string{launcher}) {
+		t.Errorf("hostLauncher() = %q", got)
+	}
+
+	os.Setenv("SNAP", "/snap/app/1")
+	if got := hostLauncher(); got != nil {
+		t.Errorf("hostLauncher() = %q, want nil", got)
+	}
+}
+
+func TestSandboxPath(t *testing.T) {
+	defer func(root string) { hostRoot = root }(hostRoot)
+	hostRoot = t.TempDir()
+	if err := os.MkdirAll(filepath.Join(hostRoot, "zenity-test"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	b := Backend{Tool: "zenity", Path: "zenity", Host: []string{"flatpak-spawn", "--host"}}
+	local := t.TempDir()
+	tests := []struct {
+		host, sandbox string
+	}{
+		{"/zenity-test/data.txt", filepath.Join(hostRoot, "zenity-test/data.txt")},
+		{filepath.Join(local, "data.txt"), filepath.Join(local, "data.txt")},
+		{"/nonexistent/data.txt", "/nonexistent/data.txt"},
+	}
+	for _, tt := range tests {
+		if got := b.SandboxPath(tt.host); got != tt.sandbox {
+			t.Errorf("SandboxPath(%q) = %q, want %q", tt.host, got, tt.sandbox)
+		}
+		if got := b.HostPath(tt.sandbox); got != tt.host {
+			t.Errorf("HostPath(%q) = %q, want %q", tt.sandbox, got, tt.host)
+		}
+	}
+
+	if got := (Backend{Path: "/usr/bin/zenity"}).SandboxPath("/zenity-test/data.txt"); !strings.HasPrefix(got, "/zenity-test") {
+		t.Errorf("SandboxPath() = %q outside a sandbox", got)
+	}
+}
+
+func TestHostCommand(t *testing.T) {
+	env := []string{"PATH=" + os.Getenv("PATH"), "ZENITY_TEST=1"}
+	tests := []struct {
+		host []string
+		want []string
+	}{
+		{[]string{"flatpak-spawn", "--host"}, []string{"--host", "--env=ZENITY_TEST=1", "zenity", "--info"}},
+		{[]string{"distrobox-host-exec"}, []string{"env", "ZENITY_TEST=1", "zenity", "--info"}},
+	}
+	for _, tt := range tests {
+		b := Backend{Tool: "zenity", Path: "zenity", Host: tt.host}
+		name, args := b.command([]string{"--info"}, env)
+		if name != tt.host[0] || !reflect.DeepEqual(args, tt.want) {
+			t.Errorf("command() = %q, %q", name, args)
+		}
+	}
+}
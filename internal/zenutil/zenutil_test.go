@@ -0,0 +1,26 @@
+package zenutil
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+func TestWithCommand(t *testing.T) {
+	if IsCommand(nil) || IsCommand(context.Background()) {
+		t.Error("IsCommand() = true, want false")
+	}
+	ctx, cancel := context.WithCancel(WithCommand(context.Background()))
+	defer cancel()
+	if !IsCommand(ctx) {
+		t.Error("IsCommand() = false, want true")
+	}
+	if IsCommand(WithoutCommand(ctx)) || WithoutCommand(nil) != nil {
+		t.Error("IsCommand(WithoutCommand()) = true, want false")
+	}
+}
@@ -0,0 +1,84 @@
+package zenity
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+// Markup returns an Option to interpret the dialog text as Pango markup.
+//
+// Use Escape, Bold and Link to build markup safely.
+// Where markup is not supported, it is converted to plain text.
+func Markup() Option {
+	return funcOption(func(o *options) { o.markup = true })
+}
+
+var escaper = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	"'", "&apos;",
+	`"`, "&quot;",
+)
+
+// Escape returns text escaped for use in Pango markup.
+func Escape(text string) string {
+	return escaper.Replace(text)
+}
+
+// Bold returns Pango markup that shows text in bold.
+func Bold(text string) string {
+	return "<b>" + Escape(text) + "</b>"
+}
+
+// Link returns Pango markup that shows text as a link to uri.
+func Link(uri, text string) string {
+	return `<a href="` + Escape(uri) + `">` + Escape(text) + "</a>"
+}
+
+// plainText converts Pango markup to plain text.
+// Links are followed by their URI, unless it is also their text.
+// Invalid markup is returned unchanged.
+func plainText(markup string) string {
+	var buf strings.Builder
+	var href, text []string
+
+	dec := xml.NewDecoder(strings.NewReader("<markup>" + markup + "</markup>"))
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return buf.String()
+		}
+		if err != nil {
+			return markup
+		}
+
+		switch tok := tok.(type) {
+		case xml.CharData:
+			buf.Write(tok)
+			if len(text) > 0 {
+				text[len(text)-1] += string(tok)
+			}
+		case xml.StartElement:
+			if tok.Name.Local == "a" {
+				var uri string
+				for _, a := range tok.Attr {
+					if a.Name.Local == "href" {
+						uri = a.Value
+					}
+				}
+				href = append(href, uri)
+				text = append(text, "")
+			}
+		case xml.EndElement:
+			if tok.Name.Local == "a" && len(href) > 0 {
+				uri, txt := href[len(href)-1], text[len(text)-1]
+				href, text = href[:len(href)-1], text[:len(text)-1]
+				if uri != "" && uri != txt {
+					buf.WriteString(" (" + uri + ")")
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package zenity
+
+import "testing"
+
+func TestPlainText(t *testing.T) {
+	tests := []struct {
+		markup string
+		want   string
+	}{
+		{"plain", "plain"},
+		{Escape(`a < b & "c"`), `a < b & "c"`},
+		{"Saved " + Bold("report.txt") + ".", "Saved report.txt."},
+		{"See " + Link("https://go.dev/?a=1&b=2", "the docs"), "See the docs (https://go.dev/?a=1&b=2)"},
+		{Link("https://go.dev/", "https://go.dev/"), "https://go.dev/"},
+		{"<b>unclosed", "<b>unclosed"},
+	}
+	for _, tt := range tests {
+		if got := plainText(tt.markup); got != tt.want {
+			t.Errorf("plainText(%q) = %q, want %q", tt.markup, got, tt.want)
+		}
+	}
+}
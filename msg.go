@@ -1,5 +1,11 @@
 package zenity
 
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
 // ErrExtraButton is returned by dialog functions when the extra button is
 // pressed.
 const ErrExtraButton = constError("Extra button pressed")
@@ -8,18 +14,27 @@ const ErrExtraButton = constError("Extra button pressed")
 //
 // Returns true on OK, false on Cancel, or ErrExtraButton.
 //
-// Valid options: Title, Icon, OKLabel, CancelLabel, ExtraButton, NoWrap,
-// Ellipsize, DefaultCancel.
+// Valid options: Title, Icon, IconName, IconFile, IconImage, WindowIcon,
+// OKLabel, CancelLabel, ExtraButton, NoWrap, Ellipsize, DefaultCancel, Markup,
+// Detail, Remember, TimeoutDefault, Sensitive.
 func Question(text string, options ...Option) (bool, error) {
-	return message(questionKind, text, options)
+	if err := validate("Question", applyOptions(options)); err != nil {
+		return false, err
+	}
+	return question(text, options)
 }
 
 // Info displays the info dialog.
 //
 // Returns true on OK, false on dismiss, or ErrExtraButton.
 //
-// Valid options: Title, Icon, OKLabel, ExtraButton, NoWrap, Ellipsize.
+// Valid options: Title, Icon, IconName, IconFile, IconImage, WindowIcon,
+// OKLabel, ExtraButton, NoWrap, Ellipsize, Markup, Detail, TimeoutDefault,
+// Sensitive.
 func Info(text string, options ...Option) (bool, error) {
+	if err := validate("Info", applyOptions(options)); err != nil {
+		return false, err
+	}
 	return message(infoKind, text, options)
 }
 
@@ -27,8 +42,13 @@ func Info(text string, options ...Option) (bool, error) {
 //
 // Returns true on OK, false on dismiss, or ErrExtraButton.
 //
-// Valid options: Title, Icon, OKLabel, ExtraButton, NoWrap, Ellipsize.
+// Valid options: Title, Icon, IconName, IconFile, IconImage, WindowIcon,
+// OKLabel, ExtraButton, NoWrap, Ellipsize, Markup, Detail, TimeoutDefault,
+// Sensitive.
 func Warning(text string, options ...Option) (bool, error) {
+	if err := validate("Warning", applyOptions(options)); err != nil {
+		return false, err
+	}
 	return message(warningKind, text, options)
 }
 
@@ -36,8 +56,13 @@ func Warning(text string, options ...Option) (bool, error) {
 //
 // Returns true on OK, false on dismiss, or ErrExtraButton.
 //
-// Valid options: Title, Icon, OKLabel, ExtraButton, NoWrap, Ellipsize.
+// Valid options: Title, Icon, IconName, IconFile, IconImage, WindowIcon,
+// OKLabel, ExtraButton, NoWrap, Ellipsize, Markup, Detail, TimeoutDefault,
+// Sensitive.
 func Error(text string, options ...Option) (bool, error) {
+	if err := validate("Error", applyOptions(options)); err != nil {
+		return false, err
+	}
 	return message(errorKind, text, options)
 }
 
@@ -75,6 +100,66 @@ func Ellipsize() Option {
 	return funcOption(func(o *options) { o.ellipsize = true })
 }
 
+// Detail returns an Option to add secondary text, shown below the main text
+// of the dialog.
+func Detail(detail string) Option {
+	return funcOption(func(o *options) { o.detail = detail })
+}
+
+// TimeoutDefault returns an Option to dismiss the dialog after a timeout,
+// choosing a default answer, and to display the time remaining.
+//
+// On Unix, dialogs without an extra button are displayed as a progress dialog
+// that counts down; other dialogs display the time the answer is chosen.
+//
+// Dialogs dismissed by the timeout return answer instead of an error.
+// A timeout of zero or less is ignored.
+func TimeoutDefault(timeout time.Duration, answer bool) Option {
+	return funcOption(func(o *options) {
+		if timeout <= 0 {
+			return
+		}
+		o.timeout = timeout
+		o.timeoutDefault = true
+		o.timeoutAnswer = answer
+	})
+}
+
+// countdown describes the answer chosen when a dialog times out.
+func countdown(label string, remaining time.Duration) string {
+	secs := (remaining + time.Second - 1) / time.Second
+	return fmt.Sprintf("Choosing “%s” automatically in %d seconds.", label, secs)
+}
+
+// countdownAt describes the answer chosen when a dialog times out, for dialogs
+// that can't update their text, with the time of day it is chosen.
+func countdownAt(label string, timeout time.Duration) string {
+	at := time.Now().Add(timeout).Format("15:04:05")
+	return fmt.Sprintf("Choosing “%s” automatically at %s.", label, at)
+}
+
+// defaultLabel returns the label of the button for the TimeoutDefault answer,
+// given the default labels of the OK and Cancel buttons.
+func defaultLabel(kind messageKind, opts options, ok, cancel string) string {
+	if kind != questionKind || opts.timeoutAnswer {
+		if opts.okLabel != "" {
+			return opts.okLabel
+		}
+		return ok
+	}
+	if opts.cancelLabel != "" {
+		return opts.cancelLabel
+	}
+	return cancel
+}
+
+// timedOut reports whether err is due to the timeout of TimeoutDefault,
+// and not to the Context of ctx.
+func timedOut(ctx context.Context, opts options, err error) bool {
+	return opts.timeoutDefault && err == context.DeadlineExceeded &&
+		(ctx == nil || ctx.Err() == nil)
+}
+
 // DefaultCancel returns an Option to give Cancel button focus by default.
 func DefaultCancel() Option {
 	return funcOption(func(o *options) { o.defaultCancel = true })
@@ -2,21 +2,41 @@ package zenity
 
 import (
 	"os/exec"
+	"time"
 
 	"github.com/ncruces/zenity/internal/zenutil"
 )
 
 func message(kind messageKind, text string, options []Option) (bool, error) {
 	opts := applyOptions(options)
+	cleanup, err := writeIcons(&opts)
+	if err != nil {
+		return false, err
+	}
+	defer cleanup()
+	if opts.markup {
+		text = plainText(text)
+	}
 	data := zenutil.Msg{
 		Text:    text,
-		Timeout: zenutil.Timeout,
+		Timeout: int((opts.timeout + time.Second - 1) / time.Second),
+	}
+	dialog := kind == questionKind || opts.icon != nil
+
+	detail := opts.detail
+	if opts.timeoutDefault {
+		if detail != "" {
+			detail += "\n\n"
+		}
+		detail += countdownAt(defaultLabel(kind, opts, "OK", "Cancel"), opts.timeout)
 	}
-	dialog := kind == questionKind || opts.icon != 0
 
 	if dialog {
 		data.Operation = "displayDialog"
 		data.Title = opts.title
+		if detail != "" {
+			data.Text += "\n\n" + detail
+		}
 
 		switch opts.icon {
 		case ErrorIcon:
@@ -26,11 +46,18 @@ func message(kind messageKind, text string, options []Option) (bool, error) {
 		case InfoIcon, QuestionIcon:
 			data.Icon = "note"
 		}
+		if file, ok := opts.icon.(iconFile); ok {
+			data.IconPath = string(file)
+		}
 	} else {
 		data.Operation = "displayAlert"
+		data.Message = detail
 		if opts.title != "" {
-			data.Message = text
 			data.Text = opts.title
+			data.Message = text
+			if detail != "" {
+				data.Message += "\n\n" + detail
+			}
 		}
 
 		switch kind {
@@ -87,6 +114,9 @@ func message(kind messageKind, text string, options []Option) (bool, error) {
 	}
 
 	out, err := zenutil.Run(opts.ctx, "msg", data)
+	if timedOut(opts.ctx, opts, err) {
+		return opts.timeoutAnswer, nil
+	}
 	if err, ok := err.(*exec.ExitError); ok && err.ExitCode() == 1 {
 		return false, nil
 	}
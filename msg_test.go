@@ -17,6 +17,13 @@ func ExampleError() {
 	// Output:
 }
 
+func ExampleShowError() {
+	_, err := os.Open("settings.json")
+	zenity.ShowError(err,
+		zenity.Title("Error"))
+	// Output:
+}
+
 func ExampleInfo() {
 	zenity.Info("All updates are complete.",
 		zenity.Title("Information"),
@@ -24,6 +31,14 @@ func ExampleInfo() {
 	// Output:
 }
 
+func ExampleInfo_markup() {
+	zenity.Info("Saved "+zenity.Bold("report.txt")+" to "+
+		zenity.Link("file:///tmp", "/tmp")+".",
+		zenity.Title("Information"),
+		zenity.Markup())
+	// Output:
+}
+
 func ExampleWarning() {
 	zenity.Warning("Are you sure you want to proceed?",
 		zenity.Title("Warning"),
@@ -3,63 +3,281 @@
 package zenity
 
 import (
+	"fmt"
+	"io"
+	"os"
 	"os/exec"
+	"strings"
+	"time"
 
 	"github.com/ncruces/zenity/internal/zenutil"
 )
 
-func message(kind messageKind, text string, options []Option) (bool, error) {
+// Texts longer than this are displayed in a scrollable text-info dialog,
+// fed over stdin, as they'd make giant windows, and could exceed ARG_MAX.
+const (
+	maxMessageText  = 4096
+	maxMessageLines = 40
+)
+
+func message(kind messageKind, text string, options []Option) (ok bool, err error) {
 	opts := applyOptions(options)
+	err = withBackend(&opts, func() error {
+		ok, err = messageWith(kind, text, opts)
+		return err
+	})
+	return ok, err
+}
+
+func messageWith(kind messageKind, text string, opts options) (bool, error) {
+	cleanup, err := writeIcons(&opts)
+	if err != nil {
+		return false, err
+	}
+	defer cleanup()
+
+	if opts.sensitive || len(text) > maxMessageText || strings.Count(text, "\n") > maxMessageLines {
+		return messageText(kind, text, opts)
+	}
+	if opts.timeoutDefault && opts.extraButton == "" {
+		return messageCountdown(kind, text, opts)
+	}
+
+	if opts.detail != "" || opts.timeoutDefault {
+		if !opts.markup {
+			text = Escape(text)
+			opts.markup = true
+		}
+		text += "\n\n<small>"
+		if opts.detail != "" {
+			text += Escape(opts.detail)
+			if opts.timeoutDefault {
+				text += "\n\n"
+			}
+		}
+		if opts.timeoutDefault {
+			label := defaultLabel(kind, opts, "OK", "Cancel")
+			if kind == questionKind {
+				label = defaultLabel(kind, opts, "Yes", "No")
+			}
+			text += Escape(countdownAt(label, opts.timeout))
+		}
+		text += "</small>"
+	}
+	if opts.extraButton != "" && !supports(ExtraButtonFeature, opts) {
+		return messageList(kind, text, opts)
+	}
+
+	// Zenity 4 reversed the order of the buttons. To keep the layout of other
+	// versions, dialogs with an extra button suppress the stock buttons, and
+	// add them all as extra buttons, in order.
+	switched := opts.extraButton != "" && zenity4(opts)
+	if switched {
+		// The answer is translated, so the program can't replace us.
+		opts.ctx = zenutil.WithoutCommand(opts.ctx)
+	}
+	okLabel, cancelLabel := opts.okLabel, opts.cancelLabel
+	if okLabel == "" {
+		okLabel = "OK"
+		if kind == questionKind {
+			okLabel = "Yes"
+		}
+	}
+	if cancelLabel == "" {
+		cancelLabel = "No"
+	}
 
 	var args []string
-	switch kind {
-	case questionKind:
+	switch {
+	case switched:
+		args = append(args, "--question", "--switch")
+		if opts.icon == nil {
+			opts.icon = kindIcon(kind)
+		}
+		if opts.title == "" {
+			opts.title = kindTitle(kind)
+		}
+	case kind == questionKind:
 		args = append(args, "--question")
-	case infoKind:
+	case kind == infoKind:
 		args = append(args, "--info")
-	case warningKind:
+	case kind == warningKind:
 		args = append(args, "--warning")
-	case errorKind:
+	case kind == errorKind:
 		args = append(args, "--error")
 	}
 	if text != "" {
-		args = append(args, "--text", text, "--no-markup")
-	}
-	if opts.title != "" {
-		args = append(args, "--title", opts.title)
-	}
-	if opts.okLabel != "" {
-		args = append(args, "--ok-label", opts.okLabel)
-	}
-	if opts.cancelLabel != "" {
-		args = append(args, "--cancel-label", opts.cancelLabel)
+		args = append(args, "--text", text)
+		if !opts.markup {
+			args = append(args, "--no-markup")
+		}
 	}
-	if opts.extraButton != "" {
+	args = appendGeneral(args, opts)
+	switch {
+	case switched:
 		args = append(args, "--extra-button", opts.extraButton)
+		if kind == questionKind {
+			args = append(args, "--extra-button", cancelLabel)
+		}
+		args = append(args, "--extra-button", okLabel)
+	default:
+		if opts.okLabel != "" {
+			args = append(args, "--ok-label", opts.okLabel)
+		}
+		if opts.cancelLabel != "" {
+			args = append(args, "--cancel-label", opts.cancelLabel)
+		}
+		if opts.extraButton != "" {
+			args = append(args, "--extra-button", opts.extraButton)
+		}
 	}
 	if opts.noWrap {
 		args = append(args, "--no-wrap")
 	}
-	if opts.ellipsize {
+	if opts.ellipsize && supports(EllipsizeFeature, opts) {
 		args = append(args, "--ellipsize")
 	}
 	if opts.defaultCancel {
 		args = append(args, "--default-cancel")
 	}
-	switch opts.icon {
-	case ErrorIcon:
-		args = append(args, "--window-icon=error", "--icon-name=dialog-error")
-	case WarningIcon:
-		args = append(args, "--window-icon=warning", "--icon-name=dialog-warning")
-	case InfoIcon:
-		args = append(args, "--window-icon=info", "--icon-name=dialog-information")
-	case QuestionIcon:
-		args = append(args, "--window-icon=question", "--icon-name=dialog-question")
+	args = appendIcon(args, opts)
+
+	out, err := run(opts, args)
+	if timedOut(opts.ctx, opts, err) {
+		return opts.timeoutAnswer, nil
+	}
+	if err, ok := err.(*exec.ExitError); ok && err.ExitCode() != 255 {
+		if opts.extraButton != "" && lastLine(out) == opts.extraButton {
+			return false, ErrExtraButton
+		}
+		if switched && lastLine(out) == okLabel {
+			return true, nil
+		}
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, err
+}
+
+// kindIcon returns the stock icon of message dialogs of kind.
+func kindIcon(kind messageKind) DialogIcon {
+	switch kind {
+	case infoKind:
+		return InfoIcon
+	case warningKind:
+		return WarningIcon
+	case errorKind:
+		return ErrorIcon
+	}
+	return QuestionIcon
+}
+
+// kindTitle returns the default title of message dialogs of kind.
+func kindTitle(kind messageKind) string {
+	switch kind {
+	case infoKind:
+		return "Information"
+	case warningKind:
+		return "Warning"
+	case errorKind:
+		return "Error"
+	}
+	return "Question"
+}
+
+// messageList emulates the extra button with a list of choices,
+// for programs that don't support it.
+func messageList(kind messageKind, text string, opts options) (bool, error) {
+	okLabel := opts.okLabel
+	if okLabel == "" {
+		okLabel = "OK"
+		if kind == questionKind {
+			okLabel = "Yes"
+		}
 	}
+	// The answer is translated, so the program can't replace us.
+	opts.ctx = zenutil.WithoutCommand(opts.ctx)
 
-	out, err := zenutil.Run(opts.ctx, args)
+	args := []string{"--list", "--hide-header", "--column="}
+	if text != "" {
+		// List dialogs always interpret their text as markup.
+		if !opts.markup {
+			text = Escape(text)
+		}
+		args = append(args, "--text", text)
+	}
+	args = appendGeneral(args, opts)
+	if kind == questionKind && opts.cancelLabel != "" {
+		args = append(args, "--cancel-label", opts.cancelLabel)
+	}
+	args = appendIcon(args, opts)
+	args = append(args, okLabel, opts.extraButton)
+
+	out, err := run(opts, args)
+	if timedOut(opts.ctx, opts, err) {
+		return opts.timeoutAnswer, nil
+	}
+	if err, ok := err.(*exec.ExitError); ok && err.ExitCode() != 255 {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if lastLine(out) == opts.extraButton {
+		return false, ErrExtraButton
+	}
+	return true, nil
+}
+
+// messageText displays a long message in a scrollable text-info dialog,
+// with the buttons of the message dialog.
+func messageText(kind messageKind, text string, opts options) (bool, error) {
+	if opts.markup {
+		text = plainText(text)
+	}
+	if opts.detail != "" {
+		text += "\n\n" + opts.detail
+	}
+
+	okLabel, cancelLabel := "OK", "Close"
+	if kind == questionKind {
+		okLabel, cancelLabel = "Yes", "No"
+	}
+	if opts.timeoutDefault {
+		text += "\n\n" + countdownAt(defaultLabel(kind, opts, okLabel, cancelLabel), opts.timeout)
+	}
+	if opts.okLabel != "" {
+		okLabel = opts.okLabel
+	}
+	if opts.cancelLabel != "" && kind == questionKind {
+		cancelLabel = opts.cancelLabel
+	}
+
+	if opts.title == "" {
+		opts.title = kindTitle(kind)
+	}
+	if opts.width == 0 && opts.height == 0 {
+		opts.width, opts.height = 600, 400
+	}
+	if opts.windowIcon == nil {
+		opts.windowIcon = opts.icon
+	}
+
+	args := []string{"--text-info"}
+	args = appendGeneral(args, opts)
+	args = append(args, "--ok-label", okLabel, "--cancel-label", cancelLabel)
+	if opts.extraButton != "" && supports(ExtraButtonFeature, opts) {
+		args = append(args, "--extra-button", opts.extraButton)
+	}
+
+	out, err := runInput(opts, args, strings.NewReader(text))
+	if timedOut(opts.ctx, opts, err) {
+		return opts.timeoutAnswer, nil
+	}
 	if err, ok := err.(*exec.ExitError); ok && err.ExitCode() != 255 {
-		if len(out) > 0 && string(out[:len(out)-1]) == opts.extraButton {
+		if opts.extraButton != "" && lastLine(out) == opts.extraButton {
 			return false, ErrExtraButton
 		}
 		return false, nil
@@ -69,3 +287,94 @@ func message(kind messageKind, text string, options []Option) (bool, error) {
 	}
 	return true, err
 }
+
+// progressEscaper escapes the text of progress dialogs, which is read
+// a line at a time, and then unescaped.
+var progressEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+
+// messageCountdown displays a message as a progress dialog, as message dialogs
+// can't update their text: the text and the bar show the time remaining
+// until the TimeoutDefault answer is chosen.
+func messageCountdown(kind messageKind, text string, opts options) (bool, error) {
+	if opts.markup {
+		text = plainText(text)
+	}
+	if opts.detail != "" {
+		text += "\n\n" + opts.detail
+	}
+
+	okLabel, cancelLabel := "OK", "Cancel"
+	if kind == questionKind {
+		okLabel, cancelLabel = "Yes", "No"
+	}
+	label := defaultLabel(kind, opts, okLabel, cancelLabel)
+	if opts.okLabel != "" {
+		okLabel = opts.okLabel
+	}
+	if opts.cancelLabel != "" {
+		cancelLabel = opts.cancelLabel
+	}
+	if opts.title == "" {
+		opts.title = kindTitle(kind)
+	}
+	if opts.windowIcon == nil {
+		opts.windowIcon = opts.icon
+	}
+
+	args := []string{"--progress"}
+	args = appendGeneral(args, opts)
+	args = append(args, "--ok-label", okLabel)
+	if kind == questionKind {
+		args = append(args, "--cancel-label", cancelLabel)
+	} else {
+		args = append(args, "--no-cancel")
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		return false, err
+	}
+	defer r.Close()
+	done := make(chan struct{})
+	go func() {
+		defer w.Close()
+		writeCountdown(w, text, label, opts.timeout, done)
+	}()
+	_, err = runInput(opts, args, r)
+	close(done)
+
+	if timedOut(opts.ctx, opts, err) {
+		return opts.timeoutAnswer, nil
+	}
+	if err, ok := err.(*exec.ExitError); ok && err.ExitCode() != 255 {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// writeCountdown feeds a progress dialog the time remaining until timeout,
+// every second, until done is closed.
+func writeCountdown(w io.Writer, text, label string, timeout time.Duration, done <-chan struct{}) {
+	deadline := time.Now().Add(timeout)
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	// Completing the progress enables the OK button.
+	fmt.Fprintln(w, 100)
+	for {
+		remaining := time.Until(deadline)
+		if remaining < 0 {
+			remaining = 0
+		}
+		fmt.Fprintf(w, "%d\n#%s\n", 100*remaining/timeout,
+			progressEscaper.Replace(text+"\n\n"+countdown(label, remaining)))
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
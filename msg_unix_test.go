@@ -0,0 +1,94 @@
+// +build !windows,!darwin
+
+package zenity_test
+
+import (
+	"context"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ncruces/zenity"
+)
+
+func TestMessageDetail(t *testing.T) {
+	fakeSession(t)
+	path := stub(t, "zenity", "3.32.0", "")
+
+	_, err := zenity.Error("Can't save <file>.", zenity.Executable(path),
+		zenity.Detail("Disk & quota full."))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args := stubArgs(t, path)
+	if !hasArg(args, "Can&apos;t save &lt;file&gt;.\n\n<small>Disk &amp; quota full.</small>") || hasArg(args, "--no-markup") {
+		t.Errorf("unexpected text: %q", args)
+	}
+}
+
+func TestMessageTimeoutDefault(t *testing.T) {
+	fakeSession(t)
+	path := stub(t, "zenity", "3.32.0", `head -n 3 > "$0.stdin"; exit 5`)
+
+	ok, err := zenity.Question("Reboot now?", zenity.Executable(path),
+		zenity.TimeoutDefault(30*time.Second, true))
+	if !ok || err != nil {
+		t.Errorf("Question() = %v, %v", ok, err)
+	}
+
+	args := stubArgs(t, path)
+	if !hasArg(args, "--progress") || !hasArg(args, "--timeout") || !hasArg(args, "30") {
+		t.Errorf("missing timeout: %q", args)
+	}
+	stdin, _ := ioutil.ReadFile(path + ".stdin")
+	if !strings.Contains(string(stdin), `#Reboot now?\n\nChoosing “Yes” automatically in 30 seconds.`) {
+		t.Errorf("missing countdown: %q", stdin)
+	}
+
+	zero := stub(t, "zenity", "3.32.0", "")
+	ok, err = zenity.Question("Reboot now?", zenity.Executable(zero),
+		zenity.TimeoutDefault(0, false))
+	if !ok || err != nil {
+		t.Errorf("Question() = %v, %v", ok, err)
+	}
+	if args := stubArgs(t, zero); hasArg(args, "--timeout") || hasArg(args, "--progress") {
+		t.Errorf("zero timeout was not ignored: %q", args)
+	}
+
+	_, err = zenity.Question("Reboot now?", zenity.Executable(path),
+		zenity.Timeout(30*time.Second))
+	if err != context.DeadlineExceeded {
+		t.Errorf("Question() = %v, want DeadlineExceeded", err)
+	}
+}
+
+func TestMessageLongText(t *testing.T) {
+	fakeSession(t)
+	path := stub(t, "zenity", "3.32.0", `cat > "$0.stdin"`)
+
+	text := strings.Repeat("All work and no play makes Jack a dull boy.\n", 100)
+	ok, err := zenity.Warning(text, zenity.Executable(path))
+	if !ok || err != nil {
+		t.Errorf("Warning() = %v, %v", ok, err)
+	}
+
+	args := stubArgs(t, path)
+	if !hasArg(args, "--text-info") || hasArg(args, "--text") || !hasArg(args, "Warning") {
+		t.Errorf("unexpected arguments: %q", args)
+	}
+	if stdin, _ := ioutil.ReadFile(path + ".stdin"); string(stdin) != text {
+		t.Errorf("stdin = %q", stdin)
+	}
+
+	path = stub(t, "zenity", "3.32.0", `echo Retry; exit 1`)
+	_, err = zenity.Question(text, zenity.Executable(path), zenity.ExtraButton("Retry"))
+	if err != zenity.ErrExtraButton {
+		t.Errorf("Question() = %v, want ErrExtraButton", err)
+	}
+	args = stubArgs(t, path)
+	if !hasArg(args, "--text-info") || !hasArg(args, "--cancel-label") || !hasArg(args, "No") {
+		t.Errorf("unexpected arguments: %q", args)
+	}
+}
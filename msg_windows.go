@@ -4,15 +4,30 @@ import (
 	"context"
 	"runtime"
 	"syscall"
+	"time"
 	"unsafe"
 )
 
 var (
 	messageBox = user32.NewProc("MessageBoxW")
+	getDlgItem = user32.NewProc("GetDlgItem")
 )
 
 func message(kind messageKind, text string, options []Option) (bool, error) {
 	opts := applyOptions(options)
+	ctx := opts.ctx
+	if opts.markup {
+		text = plainText(text)
+	}
+	if opts.detail != "" {
+		text += "\n\n" + opts.detail
+	}
+	defer withTimeout(&opts)()
+
+	var label string
+	if opts.timeoutDefault {
+		label = defaultLabel(kind, opts, "OK", "Cancel")
+	}
 
 	var flags uintptr
 
@@ -46,19 +61,30 @@ func message(kind messageKind, text string, options []Option) (bool, error) {
 		runtime.LockOSThread()
 		defer runtime.UnlockOSThread()
 
-		unhook, err := hookMessageLabels(kind, opts)
+		unhook, err := hookMessageLabels(kind, text, label, opts)
 		if err != nil {
 			return false, err
 		}
 		defer unhook()
 	}
 
+	if opts.modal && !opts.attach {
+		flags |= 0x2000 // MB_TASKMODAL
+	}
+
+	if opts.timeoutDefault {
+		text += "\n\n" + countdown(label, opts.timeout)
+	}
+
 	activate()
-	s, _, err := messageBox.Call(0,
+	s, _, err := messageBox.Call(ownerWindow(opts),
 		uintptr(unsafe.Pointer(syscall.StringToUTF16Ptr(text))),
 		uintptr(unsafe.Pointer(syscall.StringToUTF16Ptr(opts.title))), flags)
 
 	if opts.ctx != nil && opts.ctx.Err() != nil {
+		if timedOut(ctx, opts, opts.ctx.Err()) {
+			return opts.timeoutAnswer, nil
+		}
 		return false, opts.ctx.Err()
 	}
 	if s == 0 {
@@ -73,8 +99,12 @@ func message(kind messageKind, text string, options []Option) (bool, error) {
 	return false, nil
 }
 
-func hookMessageLabels(kind messageKind, opts options) (unhook context.CancelFunc, err error) {
+func hookMessageLabels(kind messageKind, text, label string, opts options) (unhook context.CancelFunc, err error) {
 	return hookDialog(opts.ctx, func(wnd uintptr) {
+		if opts.timeoutDefault {
+			deadline, _ := opts.ctx.Deadline()
+			go updateCountdown(opts.ctx, wnd, text, label, deadline)
+		}
 		enumChildWindows.Call(wnd,
 			syscall.NewCallback(func(wnd, lparam uintptr) uintptr {
 				name := [8]uint16{}
@@ -105,3 +135,21 @@ func hookMessageLabels(kind messageKind, opts options) (unhook context.CancelFun
 			}), 0)
 	})
 }
+
+// updateCountdown updates the text of message box wnd with the time remaining
+// until deadline, while ctx is not done.
+func updateCountdown(ctx context.Context, wnd uintptr, text, label string, deadline time.Time) {
+	ticker := time.NewTicker(time.Second / 4)
+	defer ticker.Stop()
+
+	static, _, _ := getDlgItem.Call(wnd, 0xffff) // the message text
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ptr := syscall.StringToUTF16Ptr(text + "\n\n" + countdown(label, time.Until(deadline)))
+			setWindowText.Call(static, uintptr(unsafe.Pointer(ptr)))
+		}
+	}
+}
@@ -2,7 +2,10 @@ package zenity
 
 // Notify displays a notification.
 //
-// Valid options: Title, Icon.
+// Valid options: Title, Icon, IconName, IconFile, IconImage, Markup.
 func Notify(text string, options ...Option) error {
+	if err := validate("Notify", applyOptions(options)); err != nil {
+		return err
+	}
 	return notify(text, options)
 }
@@ -8,6 +8,9 @@ import (
 
 func notify(text string, options []Option) error {
 	opts := applyOptions(options)
+	if opts.markup {
+		text = plainText(text)
+	}
 	data := zenutil.Notify{
 		Text:  text,
 		Title: opts.title,
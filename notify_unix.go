@@ -3,34 +3,64 @@
 package zenity
 
 import (
-	"github.com/ncruces/zenity/internal/zenutil"
+	"os"
+	"path/filepath"
+	"time"
 )
 
 func notify(text string, options []Option) error {
 	opts := applyOptions(options)
+	return withBackend(&opts, func() error {
+		return notifyWith(text, opts)
+	})
+}
+
+// The notification server loads icons after the notification is sent,
+// so icons written for notifications are kept for this long.
+const notifyIconLifetime = 24 * time.Hour
+
+func notifyWith(text string, opts options) error {
+	removeNotifyIcons(opts, notifyIconLifetime)
+	_, err := writeIconFiles(&opts, "zenity-notify-*.png")
+	if err != nil {
+		return err
+	}
 
 	args := []string{"--notification"}
 
 	if text != "" {
-		args = append(args, "--text", text, "--no-markup")
+		args = append(args, "--text", text)
+		if !opts.markup {
+			args = append(args, "--no-markup")
+		}
 	}
-	if opts.title != "" {
-		args = append(args, "--title", opts.title)
+	zenity4 := zenity4(opts)
+	if !zenity4 && opts.icon != nil {
+		// zenity 3 uses the window icon for notifications.
+		opts.windowIcon = opts.icon
 	}
-	switch opts.icon {
-	case ErrorIcon:
-		args = append(args, "--window-icon=error")
-	case WarningIcon:
-		args = append(args, "--window-icon=warning")
-	case InfoIcon:
-		args = append(args, "--window-icon=info")
-	case QuestionIcon:
-		args = append(args, "--window-icon=question")
+	args = appendGeneral(args, opts)
+	if zenity4 {
+		args = appendIcon(args, opts)
 	}
 
-	_, err := zenutil.Run(opts.ctx, args)
+	_, err = run(opts, args)
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+// removeNotifyIcons removes icons written for notifications older than age.
+func removeNotifyIcons(opts options, age time.Duration) {
+	dir := iconDir(opts)
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "zenity-notify-*.png"))
+	for _, f := range files {
+		if fi, err := os.Stat(f); err == nil && time.Since(fi.ModTime()) > age {
+			os.Remove(f)
+		}
+	}
+}
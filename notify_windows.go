@@ -3,9 +3,8 @@ package zenity
 import (
 	"runtime"
 	"syscall"
+	"time"
 	"unsafe"
-
-	"github.com/ncruces/zenity/internal/zenutil"
 )
 
 var (
@@ -15,6 +14,9 @@ var (
 
 func notify(text string, options []Option) error {
 	opts := applyOptions(options)
+	if opts.markup {
+		text = plainText(text)
+	}
 
 	if opts.ctx != nil && opts.ctx.Err() != nil {
 		return opts.ctx.Err()
@@ -75,7 +77,7 @@ func wtsMessage(text string, opts options) error {
 		title = "Notification"
 	}
 
-	timeout := zenutil.Timeout
+	timeout := int((opts.timeout + time.Second - 1) / time.Second)
 	if timeout == 0 {
 		timeout = 10
 	}
@@ -0,0 +1,184 @@
+package zenity
+
+import (
+	"reflect"
+	"sync"
+)
+
+// A Queue shows message dialogs one at a time.
+//
+// Dialogs shown through a Queue wait until no other dialog of the Queue is on
+// screen. Pending dialogs are shown by decreasing Priority, and in the order
+// they were queued for the same Priority.
+//
+// A dialog identical to a pending one (same kind, text and options, including
+// the Context) is merged with it, and both calls return the same result.
+// Pending dialogs whose Context expires are dropped without being shown.
+//
+// The zero value for a Queue is an empty queue ready to use.
+// A Queue must not be copied after first use.
+type Queue struct {
+	mtx     sync.Mutex
+	pending []*queued
+	running bool
+}
+
+type queued struct {
+	kind    messageKind
+	text    string
+	options []Option
+	opts    options
+	done    chan struct{}
+	ok      bool
+	err     error
+}
+
+// Question displays the question dialog, once no other dialog of q is shown.
+//
+// See Question.
+func (q *Queue) Question(text string, options ...Option) (bool, error) {
+	opts := applyOptions(options)
+	if err := validate("Question", opts, "Priority"); err != nil {
+		return false, err
+	}
+	return q.message(questionKind, text, options, opts)
+}
+
+// Info displays the info dialog, once no other dialog of q is shown.
+//
+// See Info.
+func (q *Queue) Info(text string, options ...Option) (bool, error) {
+	opts := applyOptions(options)
+	if err := validate("Info", opts, "Priority"); err != nil {
+		return false, err
+	}
+	return q.message(infoKind, text, options, opts)
+}
+
+// Warning displays the warning dialog, once no other dialog of q is shown.
+//
+// See Warning.
+func (q *Queue) Warning(text string, options ...Option) (bool, error) {
+	opts := applyOptions(options)
+	if err := validate("Warning", opts, "Priority"); err != nil {
+		return false, err
+	}
+	return q.message(warningKind, text, options, opts)
+}
+
+// Error displays the error dialog, once no other dialog of q is shown.
+//
+// See Error.
+func (q *Queue) Error(text string, options ...Option) (bool, error) {
+	opts := applyOptions(options)
+	if err := validate("Error", opts, "Priority"); err != nil {
+		return false, err
+	}
+	return q.message(errorKind, text, options, opts)
+}
+
+// Priority returns an Option to set the priority of a dialog shown through
+// a Queue. Dialogs with a higher priority are shown first.
+func Priority(priority int) Option {
+	return funcOption(func(o *options) { o.priority = priority })
+}
+
+func (q *Queue) message(kind messageKind, text string, options []Option, opts options) (bool, error) {
+	e := &queued{
+		kind:    kind,
+		text:    text,
+		options: options,
+		opts:    opts,
+		done:    make(chan struct{}),
+	}
+
+	ctx := e.opts.ctx
+	if ctx != nil && ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	q.mtx.Lock()
+	if p := q.merge(e); p != nil {
+		e = p
+	} else {
+		q.insert(e)
+	}
+	if !q.running {
+		q.running = true
+		go q.run()
+	}
+	q.mtx.Unlock()
+
+	if ctx == nil {
+		<-e.done
+		return e.ok, e.err
+	}
+	select {
+	case <-e.done:
+		return e.ok, e.err
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+}
+
+func (q *Queue) run() {
+	for {
+		q.mtx.Lock()
+		if len(q.pending) == 0 {
+			q.running = false
+			q.mtx.Unlock()
+			return
+		}
+		e := q.pending[0]
+		q.pending[0] = nil
+		q.pending = q.pending[1:]
+		q.mtx.Unlock()
+
+		if ctx := e.opts.ctx; ctx != nil && ctx.Err() != nil {
+			e.err = ctx.Err()
+		} else if e.kind == questionKind {
+			e.ok, e.err = question(e.text, e.options)
+		} else {
+			e.ok, e.err = message(e.kind, e.text, e.options)
+		}
+		close(e.done)
+	}
+}
+
+// insert adds e after all pending dialogs of the same or higher priority.
+// Must be called with q.mtx held.
+func (q *Queue) insert(e *queued) {
+	i := len(q.pending)
+	for j, p := range q.pending {
+		if p.opts.priority < e.opts.priority {
+			i = j
+			break
+		}
+	}
+	q.pending = append(q.pending, nil)
+	copy(q.pending[i+1:], q.pending[i:])
+	q.pending[i] = e
+}
+
+// merge finds a pending dialog identical to e, raising its priority to that
+// of e if needed. Must be called with q.mtx held.
+func (q *Queue) merge(e *queued) *queued {
+	for i, p := range q.pending {
+		if p.kind != e.kind || p.text != e.text || p.opts.ctx != e.opts.ctx {
+			continue
+		}
+		a, b := p.opts, e.opts
+		a.ctx, b.ctx = nil, nil
+		a.priority, b.priority = 0, 0
+		if !reflect.DeepEqual(a, b) {
+			continue
+		}
+		if p.opts.priority < e.opts.priority {
+			p.opts.priority = e.opts.priority
+			q.pending = append(q.pending[:i], q.pending[i+1:]...)
+			q.insert(p)
+		}
+		return p
+	}
+	return nil
+}
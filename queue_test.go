@@ -0,0 +1,50 @@
+package zenity_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/ncruces/zenity"
+)
+
+func ExampleQueue() {
+	var queue zenity.Queue
+	var wg sync.WaitGroup
+
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			queue.Error("Disk is almost full.",
+				zenity.Title("Error"),
+				zenity.Icon(zenity.ErrorIcon))
+		}()
+	}
+
+	queue.Warning("Battery is low.",
+		zenity.Title("Warning"),
+		zenity.Icon(zenity.WarningIcon),
+		zenity.Priority(1))
+	wg.Wait()
+	// Output:
+}
+
+func TestQueueCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var queue zenity.Queue
+	for _, f := range []func(string, ...zenity.Option) (bool, error){
+		queue.Error,
+		queue.Info,
+		queue.Warning,
+		queue.Question,
+	} {
+		_, err := f("text", zenity.Context(ctx))
+		if !errors.Is(err, context.Canceled) {
+			t.Error("was not canceled:", err)
+		}
+	}
+}
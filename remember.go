@@ -0,0 +1,169 @@
+package zenity
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+)
+
+// Remember returns an Option to offer the user to remember their answer to
+// a question, under key.
+//
+// On Unix, the question shows a "Don't ask again" checkbox, which only the Yes
+// button can report, so a No answer is followed by a second prompt that asks
+// whether to remember it. Elsewhere, and for questions that can't show
+// a checkbox (e.g. those with an extra button, or a detail), that prompt
+// follows every answer. Later questions with the same key return
+// the remembered answer without displaying anything.
+//
+// Answers are stored in a file under $XDG_STATE_HOME (or the user
+// configuration directory on Windows and macOS), one for each program.
+func Remember(key string) Option {
+	return funcOption(func(o *options) { o.remember = key })
+}
+
+// RememberedAnswers returns the answers remembered with Remember, by key.
+func RememberedAnswers() (map[string]bool, error) {
+	answersMutex.Lock()
+	defer answersMutex.Unlock()
+	return loadAnswers()
+}
+
+// ForgetAnswers forgets the answers remembered with Remember under keys,
+// or all answers if no keys are given.
+func ForgetAnswers(keys ...string) error {
+	answersMutex.Lock()
+	defer answersMutex.Unlock()
+
+	if len(keys) == 0 {
+		err := os.Remove(answersFile())
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	answers, err := loadAnswers()
+	if err != nil {
+		return err
+	}
+	for _, k := range keys {
+		delete(answers, k)
+	}
+	return saveAnswers(answers)
+}
+
+func question(text string, options []Option) (bool, error) {
+	opts := applyOptions(options)
+	if opts.remember == "" {
+		return message(questionKind, text, options)
+	}
+
+	answersMutex.Lock()
+	answers, err := loadAnswers()
+	answersMutex.Unlock()
+	if ok, found := answers[opts.remember]; found && err == nil {
+		return ok, nil
+	}
+
+	ok, save, err := askRemember(text, opts, options)
+	if !save || err != nil {
+		return ok, err
+	}
+
+	answersMutex.Lock()
+	defer answersMutex.Unlock()
+	answers, err = loadAnswers()
+	if err == nil {
+		answers[opts.remember] = ok
+		err = saveAnswers(answers)
+	}
+	if err != nil {
+		// The answer is valid, even if it can't be remembered.
+		tracef(opts, "warning: answer to %s not remembered: %v", opts.remember, err)
+	}
+	return ok, nil
+}
+
+// askTwice asks a question and then, once answered, whether to remember the
+// answer.
+func askTwice(text string, options []Option) (ok, save bool, err error) {
+	ok, err = message(questionKind, text, options)
+	if err != nil {
+		return ok, false, err
+	}
+
+	return ok, askToRemember(options), nil
+}
+
+// askToRemember asks whether to remember the answer to a question.
+func askToRemember(options []Option) bool {
+	save, err := message(questionKind, "Remember this answer?",
+		append(options[:len(options):len(options)], rememberPrompt))
+	return save && err == nil
+}
+
+// rememberPrompt adapts the options of a question to the prompt that offers
+// to remember its answer.
+var rememberPrompt = funcOption(func(o *options) {
+	o.icon = QuestionIcon
+	o.okLabel = "Remember"
+	o.cancelLabel = "Don't Remember"
+	o.extraButton = ""
+	o.detail = ""
+	o.markup = false
+	o.defaultCancel = true
+	o.remember = ""
+	// Never remember an answer the user didn't give.
+	o.timeout = 0
+	o.timeoutDefault = false
+	o.timeoutAnswer = false
+})
+
+var answersMutex sync.Mutex
+
+func answersFile() string {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+			dir, _ = os.UserConfigDir()
+		} else if home, err := os.UserHomeDir(); err == nil {
+			dir = filepath.Join(home, ".local", "state")
+		}
+	}
+	return filepath.Join(dir, "zenity", programName()+".json")
+}
+
+// programName returns the name of the running program, without extension.
+func programName() string {
+	name := filepath.Base(os.Args[0])
+	return name[:len(name)-len(filepath.Ext(name))]
+}
+
+func loadAnswers() (map[string]bool, error) {
+	answers := map[string]bool{}
+	data, err := ioutil.ReadFile(answersFile())
+	if os.IsNotExist(err) {
+		return answers, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(data, &answers)
+	return answers, err
+}
+
+func saveAnswers(answers map[string]bool) error {
+	data, err := json.Marshal(answers)
+	if err != nil {
+		return err
+	}
+	file := answersFile()
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, data, 0600)
+}
@@ -0,0 +1,5 @@
+package zenity
+
+func askRemember(text string, opts options, options []Option) (ok, save bool, err error) {
+	return askTwice(text, options)
+}
@@ -0,0 +1,72 @@
+// +build !windows,!darwin
+
+package zenity
+
+import (
+	"os/exec"
+	"strings"
+)
+
+const dontAskAgain = "Don't ask again"
+
+// askRemember asks a question as a list, with a checkbox to remember the
+// answer. Questions that don't fit a list are asked twice.
+func askRemember(text string, opts options, options []Option) (ok, save bool, err error) {
+	if opts.extraButton != "" || opts.detail != "" || opts.timeoutDefault || opts.defaultCancel ||
+		opts.sensitive || len(text) > maxMessageText || strings.Count(text, "\n") > maxMessageLines {
+		return askTwice(text, options)
+	}
+	err = withBackend(&opts, func() error {
+		ok, save, err = askList(text, opts)
+		return err
+	})
+	if !ok && err == nil {
+		// The checkbox can't be read on No.
+		save = askToRemember(options)
+	}
+	return ok, save, err
+}
+
+// askList displays the list of askRemember.
+func askList(text string, opts options) (ok, save bool, err error) {
+	cleanup, err := writeIcons(&opts)
+	if err != nil {
+		return false, false, err
+	}
+	defer cleanup()
+
+	okLabel, cancelLabel := opts.okLabel, opts.cancelLabel
+	if okLabel == "" {
+		okLabel = "Yes"
+	}
+	if cancelLabel == "" {
+		cancelLabel = "No"
+	}
+	if opts.title == "" {
+		opts.title = kindTitle(questionKind)
+	}
+	if opts.windowIcon == nil {
+		opts.windowIcon = opts.icon
+	}
+	// List dialogs always interpret their text as markup.
+	if !opts.markup {
+		text = Escape(text)
+	}
+
+	args := []string{"--list", "--checklist", "--hide-header", "--column=", "--column="}
+	if text != "" {
+		args = append(args, "--text", text)
+	}
+	args = appendGeneral(args, opts)
+	args = append(args, "--ok-label", okLabel, "--cancel-label", cancelLabel)
+	args = append(args, "FALSE", dontAskAgain)
+
+	out, err := run(opts, args)
+	if err, ok := err.(*exec.ExitError); ok && err.ExitCode() != 255 {
+		return false, false, nil
+	}
+	if err != nil {
+		return false, false, err
+	}
+	return true, lastLine(out) == dontAskAgain, nil
+}
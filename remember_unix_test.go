@@ -0,0 +1,147 @@
+// +build !windows,!darwin
+
+package zenity_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ncruces/zenity"
+)
+
+func TestRemember(t *testing.T) {
+	fakeSession(t)
+	defer os.Setenv("XDG_STATE_HOME", os.Getenv("XDG_STATE_HOME"))
+	os.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	yes := stub(t, "zenity", "3.32.0", `echo "Don't ask again"`)
+	ok, err := zenity.Question("Reboot?", zenity.Executable(yes), zenity.Remember("reboot"))
+	if !ok || err != nil {
+		t.Fatalf("Question() = %v, %v", ok, err)
+	}
+	if args := stubArgs(t, yes); !hasArg(args, "--checklist") || !hasArg(args, "Don't ask again") {
+		t.Errorf("did not offer to remember: %q", args)
+	}
+
+	no := stub(t, "zenity", "3.32.0", "exit 1")
+	ok, err = zenity.Question("Reboot?", zenity.Executable(no), zenity.Remember("reboot"))
+	if !ok || err != nil {
+		t.Errorf("Question() = %v, %v", ok, err)
+	}
+	if _, err := os.Stat(no + ".args"); !os.IsNotExist(err) {
+		t.Error("remembered question was displayed")
+	}
+
+	answers, err := zenity.RememberedAnswers()
+	if err != nil || len(answers) != 1 || !answers["reboot"] {
+		t.Errorf("RememberedAnswers() = %v, %v", answers, err)
+	}
+
+	if err := zenity.ForgetAnswers("reboot"); err != nil {
+		t.Fatal(err)
+	}
+	ok, err = zenity.Question("Reboot?", zenity.Executable(no), zenity.Remember("reboot"))
+	if ok || err != nil {
+		t.Errorf("Question() = %v, %v", ok, err)
+	}
+	if err := zenity.ForgetAnswers(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRememberPrompt(t *testing.T) {
+	fakeSession(t)
+	defer os.Setenv("XDG_STATE_HOME", os.Getenv("XDG_STATE_HOME"))
+	os.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	yes := stub(t, "zenity", "3.32.0", "exit 0")
+	ok, err := zenity.Question("Reboot?", zenity.Executable(yes),
+		zenity.ExtraButton("Later"), zenity.Remember("reboot"))
+	if !ok || err != nil {
+		t.Fatalf("Question() = %v, %v", ok, err)
+	}
+	if args := stubArgs(t, yes); !hasArg(args, "Remember this answer?") {
+		t.Errorf("did not offer to remember: %q", args)
+	}
+	if answers, err := zenity.RememberedAnswers(); err != nil || !answers["reboot"] {
+		t.Errorf("RememberedAnswers() = %v, %v", answers, err)
+	}
+}
+
+func TestRememberNo(t *testing.T) {
+	fakeSession(t)
+	defer os.Setenv("XDG_STATE_HOME", os.Getenv("XDG_STATE_HOME"))
+	os.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	// No to the question, Remember to the prompt.
+	path := stub(t, "zenity", "3.32.0", `
+for arg; do
+	[ "$arg" = --list ] && exit 1
+done
+exit 0`)
+	ok, err := zenity.Question("Reboot?", zenity.Executable(path), zenity.Remember("reboot"))
+	if ok || err != nil {
+		t.Fatalf("Question() = %v, %v", ok, err)
+	}
+	if args := stubArgs(t, path); !hasArg(args, "Remember this answer?") {
+		t.Errorf("did not offer to remember: %q", args)
+	}
+	if answers, err := zenity.RememberedAnswers(); err != nil || answers["reboot"] {
+		t.Errorf("RememberedAnswers() = %v, %v", answers, err)
+	} else if _, found := answers["reboot"]; !found {
+		t.Error("No was not remembered")
+	}
+}
+
+func TestRememberCorrupt(t *testing.T) {
+	fakeSession(t)
+	state := t.TempDir()
+	defer os.Setenv("XDG_STATE_HOME", os.Getenv("XDG_STATE_HOME"))
+	os.Setenv("XDG_STATE_HOME", state)
+
+	yes := stub(t, "zenity", "3.32.0", `echo "Don't ask again"`)
+	if err := os.MkdirAll(filepath.Join(state, "zenity"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(state, "zenity", filepath.Base(os.Args[0]))
+	file = strings.TrimSuffix(file, filepath.Ext(file)) + ".json"
+	if err := ioutil.WriteFile(file, []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var trace strings.Builder
+	ok, err := zenity.Question("Reboot?", zenity.Executable(yes),
+		zenity.Trace(&trace), zenity.Remember("reboot"))
+	if !ok || err != nil {
+		t.Errorf("Question() = %v, %v", ok, err)
+	}
+	if !strings.Contains(trace.String(), "answer to reboot not remembered") {
+		t.Errorf("unexpected trace: %q", trace.String())
+	}
+}
+
+func TestRememberTimeoutDefault(t *testing.T) {
+	fakeSession(t)
+	defer os.Setenv("XDG_STATE_HOME", os.Getenv("XDG_STATE_HOME"))
+	os.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	// Answers Yes, and then lets the prompt to remember it time out.
+	path := stub(t, "zenity", "3.32.0", `
+for a; do [ "$a" = "Remember this answer?" ] && exit 5; done
+exit 0`)
+	ok, err := zenity.Question("Reboot?", zenity.Executable(path),
+		zenity.TimeoutDefault(time.Minute, true), zenity.Remember("reboot"))
+	if !ok || err != nil {
+		t.Errorf("Question() = %v, %v", ok, err)
+	}
+	if args := stubArgs(t, path); !hasArg(args, "Remember this answer?") || hasArg(args, "--timeout") {
+		t.Errorf("unexpected arguments: %q", args)
+	}
+	if answers, err := zenity.RememberedAnswers(); err != nil || len(answers) != 0 {
+		t.Errorf("RememberedAnswers() = %v, %v", answers, err)
+	}
+}
@@ -0,0 +1,221 @@
+package zenity
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// ShowError displays an error dialog reporting err.
+//
+// The dialog shows the message of err. If err wraps other errors, or formats
+// with a stack trace under the %+v verb, a Details button shows the full
+// report, and offers to copy it to the clipboard.
+//
+// Returns nil if err is nil, without displaying anything.
+//
+// Valid options: Title, Icon, IconName, IconFile, IconImage, WindowIcon,
+// OKLabel, NoWrap, Ellipsize.
+func ShowError(err error, options ...Option) error {
+	if err == nil {
+		return nil
+	}
+	if err := validate("ShowError", applyOptions(options)); err != nil {
+		return err
+	}
+
+	text := err.Error()
+	report := errorReport(err)
+	options = append(options[:len(options):len(options)], plainReport)
+	if report == text {
+		_, err := message(errorKind, text, options)
+		return err
+	}
+
+	_, e := message(errorKind, text, append(options, ExtraButton("Details")))
+	if e != ErrExtraButton {
+		return e
+	}
+	copied, e := showReport(report, options)
+	if !copied || e != nil {
+		return e
+	}
+	return copyToClipboard(report)
+}
+
+// errorReport describes err, the errors it wraps, and its stack trace.
+func errorReport(err error) string {
+	var sb strings.Builder
+	sb.WriteString(err.Error())
+	for e := errors.Unwrap(err); e != nil; e = errors.Unwrap(e) {
+		sb.WriteString("\ncaused by: ")
+		sb.WriteString(e.Error())
+	}
+	if verbose := fmt.Sprintf("%+v", err); verbose != err.Error() {
+		sb.WriteString("\n\n")
+		sb.WriteString(verbose)
+	}
+	return sb.String()
+}
+
+// reportMessage shows report in an info dialog with a Copy button,
+// for platforms without a text-info dialog.
+func reportMessage(report string, options []Option) (bool, error) {
+	_, err := message(infoKind, report, append(options[:len(options):len(options)],
+		OKLabel("Close"), ExtraButton("Copy")))
+	if err == ErrExtraButton {
+		return true, nil
+	}
+	return false, err
+}
+
+// plainReport adapts the options of a dialog to display an error report,
+// which is never markup.
+var plainReport = funcOption(func(o *options) {
+	o.extraButton = ""
+	o.detail = ""
+	o.markup = false
+	o.timeoutDefault = false
+})
+
+// RecoverAndReport recovers from a panic, reports it to the user, and then
+// panics again, with an error that formats as the original value followed by
+// its stack trace. It must be deferred directly, and only recovers panics of
+// the goroutine that deferred it:
+//
+//	defer zenity.RecoverAndReport()
+//
+// A crash report, with the stack trace, program version and a summary of the
+// environment, is written to $XDG_CACHE_HOME (or the user cache directory).
+// An error dialog shows the report path, and offers to open or copy the report.
+//
+// Valid options: Title, Icon, IconName, IconFile, IconImage, WindowIcon,
+// NoWrap, Ellipsize, ExitCode.
+func RecoverAndReport(options ...Option) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	stack := debug.Stack()
+	report := crashReport(r, stack)
+	path, err := writeCrashReport(report)
+
+	var detail string
+	if err == nil {
+		detail = fmt.Sprintf("A crash report was saved to %s.", path)
+	} else {
+		detail = fmt.Sprintf("The crash report could not be saved: %v.", err)
+	}
+	text := fmt.Sprintf("%s crashed: %v", programName(), r)
+
+	kind := questionKind
+	options = append([]Option{Icon(ErrorIcon)}, options...)
+	options = append(options, plainReport, Detail(detail), ExtraButton("Copy"))
+	if err == nil {
+		options = append(options, OKLabel("Open report"), CancelLabel("Close"))
+	} else {
+		kind = errorKind
+		options = append(options, OKLabel("Close"))
+	}
+
+	for {
+		open, err := message(kind, text, options)
+		if err != ErrExtraButton {
+			if open && kind == questionKind {
+				openFile(path)
+			}
+			break
+		}
+		copyToClipboard(report)
+	}
+
+	opts := applyOptions(options)
+	if opts.exit {
+		os.Exit(opts.exitCode)
+	}
+	panic(reportedPanic{r, stack})
+}
+
+// reportedPanic is the value RecoverAndReport panics with again, which keeps
+// the stack trace of the original panic.
+type reportedPanic struct {
+	value interface{}
+	stack []byte
+}
+
+func (p reportedPanic) Error() string {
+	return fmt.Sprintf("%v\n\n%s", p.value, p.stack)
+}
+
+func (p reportedPanic) Unwrap() error {
+	err, _ := p.value.(error)
+	return err
+}
+
+// ExitCode returns an Option to exit the program with code after reporting
+// a panic, instead of panicking again.
+func ExitCode(code int) Option {
+	return funcOption(func(o *options) {
+		o.exit = true
+		o.exitCode = code
+	})
+}
+
+// crashReport describes the panic r, its stack trace, the program version,
+// and the environment it ran in.
+func crashReport(r interface{}, stack []byte) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "panic: %v\n\n%s\n", r, stack)
+	fmt.Fprintf(&sb, "program: %q\n", os.Args)
+	if info, ok := debug.ReadBuildInfo(); ok {
+		fmt.Fprintf(&sb, "version: %s %s\n", info.Main.Path, info.Main.Version)
+	}
+	fmt.Fprintf(&sb, "go: %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&sb, "time: %s\n", time.Now().Format(time.RFC3339))
+	for _, key := range []string{
+		"LANG", "DISPLAY", "WAYLAND_DISPLAY",
+		"XDG_SESSION_TYPE", "XDG_CURRENT_DESKTOP",
+	} {
+		if val, ok := os.LookupEnv(key); ok {
+			fmt.Fprintf(&sb, "%s: %s\n", key, val)
+		}
+	}
+	return sb.String()
+}
+
+// startFile starts cmd, which opens a file, without waiting for it to exit,
+// but reaping it once it does.
+func startFile(cmd *exec.Cmd) error {
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
+
+func writeCrashReport(report string) (string, error) {
+	dir := os.Getenv("XDG_CACHE_HOME")
+	if dir == "" {
+		var err error
+		dir, err = os.UserCacheDir()
+		if err != nil {
+			return "", err
+		}
+	}
+	dir = filepath.Join(dir, "zenity")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+
+	name := programName() + "-crash-" + time.Now().Format("20060102-150405") + ".txt"
+	path := filepath.Join(dir, name)
+	return path, ioutil.WriteFile(path, []byte(report), 0600)
+}
@@ -0,0 +1,20 @@
+package zenity
+
+import (
+	"os/exec"
+	"strings"
+)
+
+func showReport(report string, options []Option) (bool, error) {
+	return reportMessage(report, options)
+}
+
+func copyToClipboard(text string) error {
+	cmd := exec.Command("pbcopy")
+	cmd.Stdin = strings.NewReader(text)
+	return cmd.Run()
+}
+
+func openFile(path string) error {
+	return startFile(exec.Command("open", path))
+}
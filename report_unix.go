@@ -0,0 +1,70 @@
+// +build !windows,!darwin
+
+package zenity
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// showReport shows report in a scrollable text-info dialog, and reports
+// whether the user asked to copy it.
+func showReport(report string, options []Option) (copied bool, err error) {
+	opts := applyOptions(options)
+	err = withBackend(&opts, func() error {
+		copied, err = showReportWith(report, opts)
+		return err
+	})
+	return copied, err
+}
+
+func showReportWith(report string, opts options) (bool, error) {
+	if opts.width == 0 && opts.height == 0 {
+		opts.width, opts.height = 600, 400
+	}
+
+	args := []string{"--text-info"}
+	args = appendGeneral(args, opts)
+	if clipboardCommand() != nil {
+		args = append(args, "--ok-label=Copy", "--cancel-label=Close")
+	} else {
+		args = append(args, "--ok-label=Close")
+	}
+
+	_, err := runInput(opts, args, strings.NewReader(report))
+	if _, ok := err.(*exec.ExitError); ok {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return clipboardCommand() != nil, nil
+}
+
+func copyToClipboard(text string) error {
+	cmd := clipboardCommand()
+	if cmd == nil {
+		return exec.ErrNotFound
+	}
+	cmd.Stdin = strings.NewReader(text)
+	return cmd.Run()
+}
+
+// clipboardCommand returns a command that copies its input to the clipboard,
+// or nil if neither wl-copy nor xclip are installed.
+func clipboardCommand() *exec.Cmd {
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		if path, err := exec.LookPath("wl-copy"); err == nil {
+			return exec.Command(path)
+		}
+	}
+	if path, err := exec.LookPath("xclip"); err == nil {
+		return exec.Command(path, "-selection", "clipboard")
+	}
+	return nil
+}
+
+func openFile(path string) error {
+	return startFile(exec.Command("xdg-open", path))
+}
@@ -0,0 +1,106 @@
+// +build !windows,!darwin
+
+package zenity_test
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ncruces/zenity"
+)
+
+func TestShowError(t *testing.T) {
+	fakeSession(t)
+	path := stub(t, "zenity", "3.32.0", `
+case " $* " in
+*" --text-info "*) cat > "$0.stdin"; exit 1 ;;
+esac
+echo Details
+exit 1
+`)
+
+	// Hide any clipboard tools, but keep cat for the stub.
+	cat, err := exec.LookPath("cat")
+	if err != nil {
+		t.Skip(err)
+	}
+	bin := t.TempDir()
+	if err := os.Symlink(cat, filepath.Join(bin, "cat")); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Setenv("PATH", os.Getenv("PATH"))
+	os.Setenv("PATH", bin)
+
+	err = zenity.ShowError(fmt.Errorf("save: %w", os.ErrPermission), zenity.Executable(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args := stubArgs(t, path)
+	if !hasArg(args, "--text-info") || !hasArg(args, "--ok-label=Close") {
+		t.Errorf("unexpected arguments: %q", args)
+	}
+	report, err := ioutil.ReadFile(path + ".stdin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "save: permission denied\ncaused by: permission denied"; string(report) != want {
+		t.Errorf("report = %q, want %q", report, want)
+	}
+}
+
+func TestShowErrorPlain(t *testing.T) {
+	fakeSession(t)
+	path := stub(t, "zenity", "3.32.0", "exit 0")
+
+	err := zenity.ShowError(errors.New("disk <full>"), zenity.Executable(path), zenity.Markup())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args := stubArgs(t, path)
+	if !hasArg(args, "--error") || !hasArg(args, "disk <full>") || hasArg(args, "--extra-button") {
+		t.Errorf("unexpected arguments: %q", args)
+	}
+}
+
+func TestRecoverAndReport(t *testing.T) {
+	fakeSession(t)
+	path := stub(t, "zenity", "3.32.0", "exit 1")
+
+	cache := t.TempDir()
+	defer os.Setenv("XDG_CACHE_HOME", os.Getenv("XDG_CACHE_HOME"))
+	os.Setenv("XDG_CACHE_HOME", cache)
+
+	defer func() {
+		r := fmt.Sprint(recover())
+		if !strings.HasPrefix(r, "boom\n\ngoroutine ") || !strings.Contains(r, "TestRecoverAndReport") {
+			t.Fatalf("recover() = %v, want boom and its stack", r)
+		}
+
+		args := stubArgs(t, path)
+		if !hasArg(args, "--question") || !hasArg(args, "Open report") {
+			t.Errorf("unexpected arguments: %q", args)
+		}
+
+		reports, _ := filepath.Glob(filepath.Join(cache, "zenity", "*-crash-*.txt"))
+		if len(reports) != 1 {
+			t.Fatalf("crash reports = %q", reports)
+		}
+		report, err := ioutil.ReadFile(reports[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(string(report), "panic: boom\n") {
+			t.Errorf("report = %q", report)
+		}
+	}()
+	defer zenity.RecoverAndReport(zenity.Executable(path))
+	panic("boom")
+}
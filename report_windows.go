@@ -0,0 +1,26 @@
+package zenity
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os/exec"
+	"unicode/utf16"
+)
+
+func showReport(report string, options []Option) (bool, error) {
+	return reportMessage(report, options)
+}
+
+func copyToClipboard(text string) error {
+	// clip.exe only decodes Unicode input as UTF-16LE, with a BOM.
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, utf16.Encode([]rune("\ufeff"+text)))
+
+	cmd := exec.Command("clip")
+	cmd.Stdin = &buf
+	return cmd.Run()
+}
+
+func openFile(path string) error {
+	return startFile(exec.Command("rundll32", "url.dll,FileProtocolHandler", path))
+}
@@ -0,0 +1,237 @@
+package zenity
+
+import (
+	"fmt"
+	"image"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync/atomic"
+)
+
+// ErrUnsupportedOption is returned by dialog functions in strict mode, when
+// given options the dialog, or the program that displays it, doesn't support.
+// The error returned wraps ErrUnsupportedOption, and lists those options.
+const ErrUnsupportedOption = constError("zenity: unsupported option")
+
+// Strict returns an Option to fail with ErrUnsupportedOption, instead of
+// ignoring options the dialog, the platform, or the program that displays
+// the dialog, doesn't support.
+//
+// RecoverAndReport, which can't return an error, ignores Strict.
+func Strict() Option {
+	return funcOption(func(o *options) { o.strict = true })
+}
+
+// SetStrict sets whether all dialogs validate their options, as if given
+// Strict. It is meant for tests.
+func SetStrict(strict bool) {
+	var v int32
+	if strict {
+		v = 1
+	}
+	atomic.StoreInt32(&strictMode, v)
+}
+
+var strictMode int32
+
+// generalOptions are valid for all dialogs.
+const generalOptions = "Title Timeout Context Executable Attach Modal Width Height WindowClass " +
+	"MinimalEnvironment DesktopUser Display Env Locale BackendChain ServedBy RawArgs Trace"
+
+const windowIconOptions = "WindowIcon WindowIconName WindowIconFile WindowIconImage "
+
+const messageOptions = windowIconOptions + "Icon IconName IconFile IconImage " +
+	"OKLabel ExtraButton NoWrap Ellipsize Markup Detail TimeoutDefault Sensitive"
+
+// validOptions lists the options of each dialog,
+// as documented in their "Valid options".
+var validOptions = map[string]string{
+	"Question":          messageOptions + " CancelLabel DefaultCancel Remember",
+	"Info":              messageOptions,
+	"Warning":           messageOptions,
+	"Error":             messageOptions,
+	"Notify":            "Icon IconName IconFile IconImage Markup",
+	"SelectFile":        windowIconOptions + "Directory Filename ShowHidden FileFilters",
+	"SelectFileMutiple": windowIconOptions + "Directory Filename ShowHidden FileFilters Separator",
+	"SelectFileSave":    windowIconOptions + "Filename ConfirmOverwrite ConfirmCreate ShowHidden FileFilters",
+	"SelectColor":       windowIconOptions + "Color ShowPalette",
+	"ConfirmPhrase":     windowIconOptions + "OKLabel CancelLabel Markup Attempts",
+	"ShowError":         windowIconOptions + "Icon IconName IconFile IconImage OKLabel NoWrap Ellipsize",
+}
+
+// platformOptions lists the options supported only on some platforms.
+var platformOptions = map[string]string{
+	"Executable":         "unix",
+	"Width":              "unix",
+	"Height":             "unix",
+	"WindowClass":        "unix",
+	"Sensitive":          "unix",
+	"MinimalEnvironment": "unix",
+	"DesktopUser":        "unix",
+	"Display":            "unix",
+	"Env":                "unix",
+	"Locale":             "unix",
+	"BackendChain":       "unix",
+	"ServedBy":           "unix",
+	"RawArgs":            "unix",
+	"Trace":              "unix",
+	"IconName":           "unix",
+	"WindowIcon":         "unix",
+	"WindowIconName":     "unix",
+	"WindowIconFile":     "unix",
+	"WindowIconImage":    "unix",
+	"Attach":             "unix windows",
+	"Modal":              "unix windows",
+	"IconFile":           "unix darwin",
+	"IconImage":          "unix darwin",
+	"Separator":          "unix darwin",
+	"ConfirmCreate":      "windows",
+	"ShowHidden":         "windows darwin",
+}
+
+// optionFields maps each field of options to the Option that sets it.
+var optionFields = map[string]string{
+	"title":            "Title",
+	"timeout":          "Timeout",
+	"executable":       "Executable",
+	"attach":           "Attach",
+	"windowID":         "Attach",
+	"modal":            "Modal",
+	"width":            "Width",
+	"height":           "Height",
+	"name":             "WindowClass",
+	"class":            "WindowClass",
+	"sensitive":        "Sensitive",
+	"minimalEnv":       "MinimalEnvironment",
+	"desktopUser":      "DesktopUser",
+	"display":          "Display",
+	"locale":           "Locale",
+	"env":              "Env",
+	"chain":            "BackendChain",
+	"servedBy":         "ServedBy",
+	"backend":          "",
+	"rawArgs":          "RawArgs",
+	"trace":            "Trace",
+	"filename":         "Filename",
+	"directory":        "Directory",
+	"confirmOverwrite": "ConfirmOverwrite",
+	"confirmCreate":    "ConfirmCreate",
+	"showHidden":       "ShowHidden",
+	"fileFilters":      "FileFilters",
+	"separator":        "Separator",
+	"color":            "Color",
+	"showPalette":      "ShowPalette",
+	"icon":             "Icon",
+	"windowIcon":       "WindowIcon",
+	"okLabel":          "OKLabel",
+	"cancelLabel":      "CancelLabel",
+	"extraButton":      "ExtraButton",
+	"noWrap":           "NoWrap",
+	"ellipsize":        "Ellipsize",
+	"defaultCancel":    "DefaultCancel",
+	"markup":           "Markup",
+	"detail":           "Detail",
+	"remember":         "Remember",
+	"priority":         "Priority",
+	"exit":             "ExitCode",
+	"exitCode":         "ExitCode",
+	"attempts":         "Attempts",
+	"timeoutDefault":   "TimeoutDefault",
+	"timeoutAnswer":    "TimeoutDefault",
+	"strict":           "",
+	"ctx":              "Context",
+}
+
+// validate checks, in strict mode, that dialog supports the options in opts.
+// Any extra options are also valid.
+func validate(dialog string, opts options, extra ...string) error {
+	if !opts.strict && atomic.LoadInt32(&strictMode) == 0 {
+		return nil
+	}
+
+	valid := strings.Fields(generalOptions + " " + validOptions[dialog])
+	valid = append(valid, extra...)
+
+	platform := runtime.GOOS
+	if platform != "windows" && platform != "darwin" {
+		platform = "unix"
+	}
+
+	var invalid, unsupported []string
+	for _, name := range optionNames(opts) {
+		switch {
+		case !contains(valid, name):
+			invalid = append(invalid, name)
+		case platformOptions[name] != "" && !contains(strings.Fields(platformOptions[name]), platform):
+			unsupported = append(unsupported, name)
+		}
+	}
+
+	var problems []string
+	if invalid != nil {
+		problems = append(problems, fmt.Sprintf("%s doesn't support %s",
+			dialog, strings.Join(invalid, ", ")))
+	}
+	if unsupported != nil {
+		problems = append(problems, fmt.Sprintf("%s doesn't support %s",
+			platformName(platform), strings.Join(unsupported, ", ")))
+	}
+	if backend, names := backendUnsupported(opts); names != nil {
+		problems = append(problems, fmt.Sprintf("%s doesn't support %s",
+			backend, strings.Join(names, ", ")))
+	}
+	if problems != nil {
+		return fmt.Errorf("%w: %s", ErrUnsupportedOption, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// optionNames returns the names of the options set in opts.
+func optionNames(opts options) []string {
+	var names []string
+	v := reflect.ValueOf(opts)
+	for i := 0; i < v.NumField(); i++ {
+		if v.Field(i).IsZero() {
+			continue
+		}
+		name := optionFields[v.Type().Field(i).Name]
+		if name == "Icon" || name == "WindowIcon" {
+			icon := opts.icon
+			if name == "WindowIcon" {
+				icon = opts.windowIcon
+			}
+			switch icon.(type) {
+			case iconName:
+				name += "Name"
+			case iconFile:
+				name += "File"
+			case image.Image:
+				name += "Image"
+			}
+		}
+		if name != "" && !contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func platformName(platform string) string {
+	switch platform {
+	case "windows":
+		return "Windows"
+	case "darwin":
+		return "macOS"
+	}
+	return "Unix"
+}
+
+func contains(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
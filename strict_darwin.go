@@ -0,0 +1,5 @@
+package zenity
+
+func backendUnsupported(opts options) (string, []string) {
+	return "", nil
+}
@@ -0,0 +1,127 @@
+package zenity
+
+import (
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestOptionFields(t *testing.T) {
+	typ := reflect.TypeOf(options{})
+	for i := 0; i < typ.NumField(); i++ {
+		if _, ok := optionFields[typ.Field(i).Name]; !ok {
+			t.Errorf("options.%s has no Option", typ.Field(i).Name)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		dialog  string
+		options []Option
+		want    string
+	}{
+		{"SelectFile", []Option{Strict(), Title("Open"), FileFilter{Patterns: []string{"*.go"}}}, ""},
+		{"SelectFile", []Option{Strict(), ConfirmOverwrite(), ShowPalette()}, "SelectFile doesn't support ConfirmOverwrite, ShowPalette"},
+		{"Info", []Option{Strict(), DefaultCancel()}, "Info doesn't support DefaultCancel"},
+		{"Question", []Option{Strict(), DefaultCancel(), Remember("reboot")}, ""},
+		{"Question", []Option{Strict(), Priority(1)}, "Question doesn't support Priority"},
+		{"Info", []Option{DefaultCancel()}, ""},
+		{"Info", []Option{Strict(), Sensitive()}, ""},
+		{"ConfirmPhrase", []Option{Strict(), Sensitive()}, "ConfirmPhrase doesn't support Sensitive"},
+		{"Notify", []Option{Strict(), Sensitive()}, "Notify doesn't support Sensitive"},
+	}
+	for _, tt := range tests {
+		err := validate(tt.dialog, applyOptions(tt.options))
+		if tt.want == "" {
+			if err != nil {
+				t.Errorf("validate(%s) = %v", tt.dialog, err)
+			}
+			continue
+		}
+		if !errors.Is(err, ErrUnsupportedOption) || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("validate(%s) = %v, want %q", tt.dialog, err, tt.want)
+		}
+	}
+
+	if err := validate("Question", applyOptions([]Option{Strict(), Priority(1)}), "Priority"); err != nil {
+		t.Errorf("validate(Queue.Question) = %v", err)
+	}
+
+	SetStrict(true)
+	defer SetStrict(false)
+	if err := validate("Info", applyOptions([]Option{DefaultCancel()})); !errors.Is(err, ErrUnsupportedOption) {
+		t.Errorf("validate(Info) = %v, want ErrUnsupportedOption", err)
+	}
+}
+
+func TestOptionNames(t *testing.T) {
+	names := optionNames(applyOptions([]Option{
+		IconName("face-smile"), WindowClass("app", "App"), TimeoutDefault(1, true),
+	}))
+	want := []string{"Timeout", "WindowClass", "IconName", "TimeoutDefault"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("optionNames() = %q, want %q", names, want)
+	}
+}
+
+// TestValidOptions checks validOptions against the "Valid options" of
+// the doc comments.
+func TestValidOptions(t *testing.T) {
+	pkgs, err := parser.ParseDir(token.NewFileSet(), ".", nil, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	documented := map[string]bool{}
+	for _, file := range pkgs["zenity"].Files {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv != nil || fn.Doc == nil {
+				continue
+			}
+			doc := fn.Doc.Text()
+			i := strings.Index(doc, "Valid options:")
+			if i < 0 {
+				continue
+			}
+			valid, ok := validOptions[fn.Name.Name]
+			if !ok {
+				// Dialogs that ignore Strict aren't validated.
+				continue
+			}
+			documented[fn.Name.Name] = true
+
+			var got []string
+			doc = strings.TrimSuffix(strings.TrimSpace(doc[i+len("Valid options:"):]), ".")
+			for _, name := range strings.Split(doc, ",") {
+				switch name = strings.TrimSpace(name); name {
+				case "FileFilter(s)":
+					got = append(got, "FileFilters")
+				case "WindowIcon":
+					got = append(got, strings.Fields(windowIconOptions)...)
+				default:
+					if !contains(strings.Fields(generalOptions), name) {
+						got = append(got, name)
+					}
+				}
+			}
+			want := strings.Fields(valid)
+			sort.Strings(got)
+			sort.Strings(want)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("%s: documented %q, validated %q", fn.Name.Name, got, want)
+			}
+		}
+	}
+	for dialog := range validOptions {
+		if !documented[dialog] {
+			t.Errorf("%s: no valid options documented", dialog)
+		}
+	}
+}
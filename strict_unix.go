@@ -0,0 +1,41 @@
+// +build !windows,!darwin
+
+package zenity
+
+// backendUnsupported returns the program that displays the dialog,
+// and the options in opts it doesn't support.
+func backendUnsupported(opts options) (string, []string) {
+	b, err := primary(opts)
+	if err != nil {
+		return "", nil
+	}
+
+	_, iconName := opts.icon.(iconName)
+	features := []struct {
+		name    string
+		feature Feature
+		set     bool
+	}{
+		{"IconName", IconNameFeature, iconName},
+		{"Ellipsize", EllipsizeFeature, opts.ellipsize},
+		{"Attach", AttachFeature, opts.attach},
+		{"Modal", ModalFeature, opts.modal},
+		{"Width", GeometryFeature, opts.width > 0},
+		{"Height", GeometryFeature, opts.height > 0},
+		{"Markup", MarkupFeature, opts.markup},
+		{"WindowClass", WindowClassFeature, opts.name != "" || opts.class != ""},
+	}
+
+	var names []string
+	for _, f := range features {
+		if f.set && !supports(f.feature, opts) {
+			names = append(names, f.name)
+		}
+	}
+
+	backend := b.Tool
+	if v := b.Version(); v != "" {
+		backend += " " + v
+	}
+	return backend, names
+}
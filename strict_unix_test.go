@@ -0,0 +1,36 @@
+// +build !windows,!darwin
+
+package zenity_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ncruces/zenity"
+)
+
+func TestStrict(t *testing.T) {
+	fakeSession(t)
+	path := stub(t, "zenity", "4.0.1", "")
+
+	_, err := zenity.SelectFile(zenity.Executable(path), zenity.Strict(),
+		zenity.Attach(42), zenity.ShowHidden(), zenity.ConfirmOverwrite())
+	if !errors.Is(err, zenity.ErrUnsupportedOption) {
+		t.Fatalf("SelectFile() = %v, want ErrUnsupportedOption", err)
+	}
+	want := "SelectFile doesn't support ConfirmOverwrite; Unix doesn't support ShowHidden; zenity 4.0.1 doesn't support Attach"
+	if !strings.HasSuffix(err.Error(), want) {
+		t.Errorf("SelectFile() = %v, want %q", err, want)
+	}
+
+	_, err = zenity.Info("text", zenity.Executable(path), zenity.Strict(), zenity.Ellipsize())
+	if err != nil {
+		t.Errorf("Info() = %v", err)
+	}
+
+	_, err = zenity.Info("text", zenity.Executable(path), zenity.Strict(), zenity.WindowClass("app", "App"))
+	if want := "zenity 4.0.1 doesn't support WindowClass"; err == nil || !strings.HasSuffix(err.Error(), want) {
+		t.Errorf("Info() = %v, want %q", err, want)
+	}
+}
@@ -0,0 +1,28 @@
+package zenity
+
+// Feature is the enumeration for dialog features that are not supported
+// everywhere.
+type Feature int
+
+// The optional dialog features.
+const (
+	ExtraButtonFeature Feature = iota + 1
+	EllipsizeFeature
+	IconNameFeature
+	AttachFeature
+	ModalFeature
+	GeometryFeature
+	MarkupFeature
+	WindowClassFeature
+)
+
+// Supports reports whether dialogs natively support a feature.
+//
+// On Unix, this depends on the program used to display dialogs, and on its
+// version. Options for unsupported features are emulated if possible,
+// or ignored.
+//
+// Valid options: Executable.
+func Supports(feature Feature, options ...Option) bool {
+	return supports(feature, applyOptions(options))
+}
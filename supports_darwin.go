@@ -0,0 +1,5 @@
+package zenity
+
+func supports(feature Feature, opts options) bool {
+	return feature == ExtraButtonFeature
+}
@@ -0,0 +1,96 @@
+// +build !windows,!darwin
+
+package zenity
+
+import (
+	"strconv"
+	"strings"
+)
+
+// minVersions holds, for each program, the first version that supports
+// a feature. Features that are not listed are not supported.
+// Unknown programs are assumed to be compatible with zenity.
+var minVersions = map[string]map[Feature]string{
+	"zenity": {
+		ExtraButtonFeature: "3.4",
+		EllipsizeFeature:   "3.16",
+		IconNameFeature:    "3.8",
+		AttachFeature:      "3.10",
+		ModalFeature:       "3.10",
+		GeometryFeature:    "3.0",
+		MarkupFeature:      "3.0",
+		WindowClassFeature: "3.0",
+	},
+	"qarma": {
+		ExtraButtonFeature: "0",
+		EllipsizeFeature:   "0",
+		AttachFeature:      "0",
+		ModalFeature:       "0",
+		GeometryFeature:    "0",
+		MarkupFeature:      "0",
+	},
+	"matedialog": {
+		ExtraButtonFeature: "1.20",
+		GeometryFeature:    "0",
+		MarkupFeature:      "0",
+		WindowClassFeature: "0",
+	},
+}
+
+// removedVersions holds the first zenity version that no longer supports
+// a feature.
+var removedVersions = map[Feature]string{
+	AttachFeature:      "4",
+	GeometryFeature:    "4",
+	WindowClassFeature: "4",
+}
+
+func supports(feature Feature, opts options) bool {
+	b, err := primary(opts)
+	if err != nil {
+		return false
+	}
+
+	features, ok := minVersions[b.Tool]
+	if !ok || b.Tool == "zenity" {
+		features = minVersions["zenity"]
+		if max, ok := removedVersions[feature]; ok && versionAtLeast(b.Version(), max) {
+			return false
+		}
+	}
+	min, ok := features[feature]
+	if !ok {
+		return false
+	}
+	version := b.Version()
+	return version == "" || versionAtLeast(version, min)
+}
+
+// zenity4 reports whether the backend is zenity 4 or later, which changed
+// some arguments.
+func zenity4(opts options) bool {
+	b, err := primary(opts)
+	if err != nil {
+		return false
+	}
+	if _, ok := minVersions[b.Tool]; ok && b.Tool != "zenity" {
+		return false
+	}
+	return versionAtLeast(b.Version(), "4")
+}
+
+func versionAtLeast(version, min string) bool {
+	v := strings.Split(version, ".")
+	m := strings.Split(min, ".")
+	for i := range m {
+		var a, b int
+		if i < len(v) {
+			a, _ = strconv.Atoi(v[i])
+		}
+		b, _ = strconv.Atoi(m[i])
+		if a != b {
+			return a > b
+		}
+	}
+	return true
+}
@@ -0,0 +1,135 @@
+// +build !windows,!darwin
+
+package zenity_test
+
+import (
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/ncruces/zenity"
+)
+
+// Zenity 4 renamed or deprecated some arguments, and writes more warnings.
+var zenityVersions = []struct {
+	version string
+	icon    string
+	gone    []string
+}{
+	{"3.44.0", "--icon-name=dialog-error", []string{"--icon=dialog-error"}},
+	{"4.0.1", "--icon=dialog-error", []string{"--icon-name=dialog-error", "--window-icon=error"}},
+}
+
+func TestMessageVersions(t *testing.T) {
+	fakeSession(t)
+	for _, v := range zenityVersions {
+		path := stub(t, "zenity", v.version, `
+echo "Gtk-WARNING: deprecated" >&2
+echo Retry
+exit 1`)
+
+		_, err := zenity.Error("text", zenity.Executable(path),
+			zenity.Icon(zenity.ErrorIcon), zenity.ExtraButton("Retry"))
+		if err != zenity.ErrExtraButton {
+			t.Errorf("zenity %s: Error() = %v, want ErrExtraButton", v.version, err)
+		}
+
+		args := stubArgs(t, path)
+		if !hasArg(args, v.icon) {
+			t.Errorf("zenity %s: missing %s: %q", v.version, v.icon, args)
+		}
+		for _, a := range v.gone {
+			if hasArg(args, a) {
+				t.Errorf("zenity %s: unexpected %s: %q", v.version, a, args)
+			}
+		}
+	}
+}
+
+func TestFileVersions(t *testing.T) {
+	fakeSession(t)
+	for _, v := range zenityVersions {
+		path := stub(t, "zenity", v.version, `
+echo "Gtk-WARNING: deprecated" >&2
+echo /tmp/file.txt`)
+
+		file, err := zenity.SelectFileSave(zenity.Executable(path), zenity.ConfirmOverwrite())
+		if file != "/tmp/file.txt" || err != nil {
+			t.Errorf("zenity %s: SelectFileSave() = %q, %v", v.version, file, err)
+		}
+
+		legacy := !zenity.VersionAtLeast(v.version, "4")
+		if args := stubArgs(t, path); hasArg(args, "--confirm-overwrite") != legacy {
+			t.Errorf("zenity %s: unexpected arguments: %q", v.version, args)
+		}
+
+		path = stub(t, "zenity", v.version, "exit 1")
+		file, err = zenity.SelectFileSave(zenity.Executable(path))
+		if file != "" || err != nil {
+			t.Errorf("zenity %s: SelectFileSave() = %q, %v", v.version, file, err)
+		}
+	}
+}
+
+func TestSelectColorVersions(t *testing.T) {
+	fakeSession(t)
+	for _, v := range zenityVersions {
+		path := stub(t, "zenity", v.version, `
+echo "Gtk-WARNING: deprecated" >&2
+echo "rgb(102,51,153)"`)
+
+		c, err := zenity.SelectColor(zenity.Executable(path))
+		if want := (color.NRGBA{R: 102, G: 51, B: 153, A: 255}); c != want || err != nil {
+			t.Errorf("zenity %s: SelectColor() = %v, %v", v.version, c, err)
+		}
+	}
+}
+
+func TestNotifyVersions(t *testing.T) {
+	fakeSession(t)
+	for _, v := range zenityVersions {
+		path := stub(t, "zenity", v.version, "")
+
+		err := zenity.Notify("text", zenity.Executable(path), zenity.Icon(zenity.ErrorIcon))
+		if err != nil {
+			t.Errorf("zenity %s: Notify() = %v", v.version, err)
+		}
+
+		args := stubArgs(t, path)
+		legacy := !zenity.VersionAtLeast(v.version, "4")
+		if hasArg(args, "--window-icon=error") != legacy || hasArg(args, "--icon=dialog-error") == legacy {
+			t.Errorf("zenity %s: unexpected arguments: %q", v.version, args)
+		}
+	}
+}
+
+func TestMessageButtonOrder(t *testing.T) {
+	fakeSession(t)
+	for _, v := range zenityVersions {
+		path := stub(t, "zenity", v.version, `
+echo Yes
+exit 1`)
+
+		ok, err := zenity.Question("text", zenity.Executable(path), zenity.ExtraButton("Later"))
+		legacy := !zenity.VersionAtLeast(v.version, "4")
+		if ok == legacy || err != nil {
+			t.Errorf("zenity %s: Question() = %v, %v", v.version, ok, err)
+		}
+
+		args := stubArgs(t, path)
+		if hasArg(args, "--switch") == legacy {
+			t.Errorf("zenity %s: unexpected arguments: %q", v.version, args)
+		}
+		if !legacy {
+			var buttons []string
+			for i, a := range args {
+				if a == "--extra-button" && i+1 < len(args) {
+					buttons = append(buttons, args[i+1])
+				}
+			}
+			if strings.Join(buttons, ",") != "Later,No,Yes" {
+				t.Errorf("zenity %s: buttons = %q", v.version, buttons)
+			}
+		}
+	}
+}
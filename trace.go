@@ -0,0 +1,42 @@
+package zenity
+
+import (
+	"fmt"
+	"io"
+)
+
+// Trace returns an Option to log how the dialog is displayed to w
+// (Unix only): which program served it, and any failures that caused
+// a fallback to the next program of the BackendChain.
+func Trace(w io.Writer) Option {
+	return funcOption(func(o *options) { o.trace = w })
+}
+
+// BackendChain returns an Option to set the programs tried, in order,
+// to display the dialog (Unix only).
+//
+// If a program fails to launch (it can't be found or started), the dialog is
+// displayed by the next one. Programs that are started are never retried,
+// whatever their exit status, as they may have displayed the dialog.
+//
+// Programs are given by name or path, and those not found are skipped.
+// Arguments are built for each program, so only programs that accept the
+// same arguments as zenity (zenity, qarma and matedialog) are tried; others,
+// like kdialog, are skipped. Without BackendChain, there is no fallback:
+// only the program set with Executable, or else the first one found in
+// the PATH, is tried.
+func BackendChain(names ...string) Option {
+	return funcOption(func(o *options) { o.chain = names })
+}
+
+// ServedBy returns an Option to store in b the program that displayed
+// the dialog (Unix only). b is left unchanged if no program launched.
+func ServedBy(b *Backend) Option {
+	return funcOption(func(o *options) { o.servedBy = b })
+}
+
+func tracef(opts options, format string, a ...interface{}) {
+	if opts.trace != nil {
+		fmt.Fprintf(opts.trace, "zenity: "+format+"\n", a...)
+	}
+}
@@ -0,0 +1,15 @@
+package zenity
+
+import "os/exec"
+
+func iconDir(opts options) string { return "" }
+
+func hostPath(opts options, path string) string { return path }
+
+func backends() []Backend {
+	path, err := exec.LookPath("osascript")
+	if err != nil {
+		return nil
+	}
+	return []Backend{{Name: "osascript", Path: path}}
+}
@@ -0,0 +1,335 @@
+// +build !windows,!darwin
+
+package zenity
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ncruces/zenity/internal/zenutil"
+)
+
+func appendGeneral(args []string, opts options) []string {
+	if opts.title != "" {
+		args = append(args, "--title", opts.title)
+	}
+	if opts.timeout > 0 {
+		secs := (opts.timeout + time.Second - 1) / time.Second
+		args = append(args, "--timeout", strconv.Itoa(int(secs)))
+	}
+	if opts.attach || opts.modal {
+		id := opts.windowID
+		if id == 0 {
+			id, _ = strconv.Atoi(os.Getenv("WINDOWID"))
+		}
+		if id != 0 && supports(AttachFeature, opts) {
+			args = append(args, "--attach", strconv.Itoa(id))
+		}
+	}
+	if opts.modal && supports(ModalFeature, opts) {
+		args = append(args, "--modal")
+	}
+	if (opts.width > 0 || opts.height > 0) && supports(GeometryFeature, opts) {
+		if opts.width > 0 {
+			args = append(args, "--width", strconv.Itoa(opts.width))
+		}
+		if opts.height > 0 {
+			args = append(args, "--height", strconv.Itoa(opts.height))
+		}
+	}
+	if (opts.name != "" || opts.class != "") && supports(WindowClassFeature, opts) {
+		if opts.name != "" {
+			args = append(args, "--name", opts.name)
+		}
+		if opts.class != "" {
+			args = append(args, "--class", opts.class)
+		}
+	}
+	if icon := windowIcon(opts.windowIcon); icon != "" && !zenity4(opts) {
+		args = append(args, "--window-icon="+icon)
+	}
+	return args
+}
+
+// appendIcon adds the dialog icon. Zenity 4 replaced --icon-name with --icon,
+// and made --window-icon a no-op.
+func appendIcon(args []string, opts options) []string {
+	var name string
+	switch icon := opts.icon.(type) {
+	case DialogIcon:
+		switch icon {
+		case ErrorIcon:
+			name = "dialog-error"
+		case WarningIcon:
+			name = "dialog-warning"
+		case InfoIcon:
+			name = "dialog-information"
+		case QuestionIcon:
+			name = "dialog-question"
+		}
+	case iconName:
+		name = string(icon)
+	case iconFile:
+		name = string(icon)
+	}
+	if name == "" {
+		return args
+	}
+
+	if zenity4(opts) {
+		return append(args, "--icon="+name)
+	}
+	if opts.windowIcon == nil {
+		args = append(args, "--window-icon="+windowIcon(opts.icon))
+	}
+	if _, file := opts.icon.(iconFile); !file && supports(IconNameFeature, opts) {
+		args = append(args, "--icon-name="+name)
+	}
+	return args
+}
+
+// windowIcon converts an icon to the --window-icon argument, which is either
+// a stock icon, a theme icon name, or a path.
+func windowIcon(icon interface{}) string {
+	switch icon := icon.(type) {
+	case DialogIcon:
+		switch icon {
+		case ErrorIcon:
+			return "error"
+		case WarningIcon:
+			return "warning"
+		case InfoIcon:
+			return "info"
+		case QuestionIcon:
+			return "question"
+		}
+	case iconName:
+		return string(icon)
+	case iconFile:
+		return string(icon)
+	}
+	return ""
+}
+
+// lastLine returns the last line of output, ignoring any diagnostics
+// written before it.
+func lastLine(out []byte) string {
+	str := strings.TrimSuffix(string(out), "\n")
+	if i := strings.LastIndexByte(str, '\n'); i >= 0 {
+		str = str[i+1:]
+	}
+	return str
+}
+
+func run(opts options, args []string) ([]byte, error) {
+	return runInput(opts, args, nil)
+}
+
+func runInput(opts options, args []string, stdin io.Reader) ([]byte, error) {
+	var env []string
+	if opts.minimalEnv {
+		env = zenutil.MinimalEnv(os.Environ())
+	}
+	var cred *syscall.Credential
+	if opts.desktopUser && os.Geteuid() == 0 {
+		var err error
+		cred, env, err = zenutil.DesktopUser("/proc")
+		if err != nil {
+			return nil, err
+		}
+	}
+	env = childEnv(env, opts)
+	if opts.ctx == nil || opts.ctx.Err() == nil {
+		if err := displayError(env, needsBus(args)); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.backend.Path != "" {
+		args = appendRawArgs(args, opts.backend, opts)
+	}
+	return zenutil.Run(opts.ctx, args, zenutil.Options{
+		Backend:    opts.backend,
+		Executable: opts.executable,
+		Stdin:      stdin,
+		Env:        env,
+		Credential: cred,
+	})
+}
+
+// withBackend displays a dialog with show, after setting in opts the program
+// that displays it. With a BackendChain, each program is tried in turn, and
+// show builds arguments for each, until one can be launched.
+func withBackend(opts *options, show func() error) error {
+	chain, skipped := chain(*opts)
+	for _, b := range skipped {
+		tracef(*opts, "%s skipped: it doesn't accept zenity's arguments", b.Tool)
+	}
+	if len(chain) == 0 {
+		if opts.chain != nil {
+			return &exec.Error{Name: "zenity", Err: exec.ErrNotFound}
+		}
+		// Fails with the reason no program was found.
+		return show()
+	}
+
+	var err error
+	for i, b := range chain {
+		opts.backend = b
+		err = show()
+		if zenutil.LaunchFailed(err) {
+			if i+1 < len(chain) {
+				tracef(*opts, "%s failed to launch: %v; trying %s", b.Tool, err, chain[i+1].Tool)
+			} else {
+				tracef(*opts, "%s failed to launch: %v", b.Tool, err)
+			}
+			continue
+		}
+
+		where := b.Path
+		if b.Host != nil {
+			where = "on the host, through " + strings.Join(b.Host, " ")
+		}
+		tracef(*opts, "dialog served by %s %s (%s)", b.Tool, b.Version(), where)
+		if opts.servedBy != nil {
+			*opts.servedBy = Backend{Name: b.Tool, Path: b.Path, Version: b.Version()}
+		}
+		return err
+	}
+	return err
+}
+
+// appendRawArgs adds the RawArgs for the program of b.
+func appendRawArgs(args []string, b zenutil.Backend, opts options) []string {
+	if opts.rawArgs == nil {
+		return args
+	}
+	args = args[:len(args):len(args)]
+	for _, raw := range opts.rawArgs {
+		if raw.tool == b.Tool {
+			args = append(args, raw.args...)
+		} else {
+			tracef(opts, "warning: raw arguments for %s ignored by %s: %q", raw.tool, b.Tool, raw.args)
+		}
+	}
+	return args
+}
+
+// chain returns the programs to try, in order, to display a dialog.
+// Without a BackendChain, that's only the one found by Lookup.
+// Programs of the BackendChain that don't accept zenity's arguments
+// are skipped.
+func chain(opts options) (found, skipped []zenutil.Backend) {
+	if opts.chain == nil {
+		if b, err := zenutil.Lookup(opts.executable); err == nil {
+			return []zenutil.Backend{b}, nil
+		}
+		return nil, nil
+	}
+
+	for _, name := range opts.chain {
+		b, err := zenutil.Lookup(name)
+		switch {
+		case err != nil:
+		case b.Compatible():
+			found = append(found, b)
+		default:
+			skipped = append(skipped, b)
+		}
+	}
+	return found, skipped
+}
+
+// primary returns the program for which arguments are built: the one being
+// tried, or else the first program of the chain.
+func primary(opts options) (zenutil.Backend, error) {
+	if opts.backend.Path != "" {
+		return opts.backend, nil
+	}
+	if chain, _ := chain(opts); len(chain) > 0 {
+		return chain[0], nil
+	}
+	return zenutil.Lookup(opts.executable)
+}
+
+// iconDir returns the directory for icon files, where the program that
+// displays the dialog can read them, or "" for the default.
+func iconDir(opts options) string {
+	b, _ := primary(opts)
+	return b.TempDir()
+}
+
+// hostPath translates path to one the program that displays the dialog
+// can open.
+func hostPath(opts options, path string) string {
+	b, _ := primary(opts)
+	return b.HostPath(path)
+}
+
+// childEnv applies the Display, Locale and Env options to env,
+// or to our environment if env is nil.
+func childEnv(env []string, opts options) []string {
+	if opts.display == "" && opts.locale == "" && opts.env == nil {
+		return env
+	}
+	if env == nil {
+		env = os.Environ()
+	}
+
+	if opts.display != "" {
+		if strings.Contains(opts.display, ":") {
+			env = unsetEnv(env, "WAYLAND_DISPLAY")
+			env = setEnv(env, "DISPLAY", opts.display)
+		} else {
+			env = setEnv(env, "WAYLAND_DISPLAY", opts.display)
+		}
+	}
+	if opts.locale != "" {
+		if locale := posixLocale(opts.locale); locale != "" {
+			env = setEnv(env, "LANG", locale)
+			env = setEnv(env, "LC_ALL", locale)
+			env = setEnv(env, "LANGUAGE", strings.SplitN(locale, ".", 2)[0])
+		} else {
+			// There's no locale for a language alone, but gettext takes one
+			// in LANGUAGE, under any locale other than C.
+			lang := strings.SplitN(opts.locale, "-", 2)[0]
+			env = setEnv(env, "LANGUAGE", strings.ToLower(lang))
+		}
+	}
+	for _, kv := range opts.env {
+		i := strings.IndexByte(kv, '=')
+		env = setEnv(env, kv[:i], kv[i+1:])
+	}
+	return env
+}
+
+func setEnv(env []string, key, value string) []string {
+	return append(unsetEnv(env, key), key+"="+value)
+}
+
+func unsetEnv(env []string, key string) []string {
+	res := make([]string, 0, len(env)+1)
+	for _, kv := range env {
+		if !strings.HasPrefix(kv, key+"=") {
+			res = append(res, kv)
+		}
+	}
+	return res
+}
+
+func backends() []Backend {
+	var res []Backend
+	for _, b := range zenutil.Backends() {
+		res = append(res, Backend{
+			Name:    b.Tool,
+			Path:    b.Path,
+			Version: b.Version(),
+		})
+	}
+	return res
+}
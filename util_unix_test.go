@@ -0,0 +1,475 @@
+// +build !windows,!darwin
+
+package zenity_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ncruces/zenity"
+)
+
+// fakeSession sets a display and a session bus for the duration of a test.
+// Stubs don't need them, but dialogs and notifications won't run without them.
+func fakeSession(t *testing.T) {
+	if !zenity.HasDisplay() {
+		setenv(t, "DISPLAY", ":0")
+	}
+	if os.Getenv("DBUS_SESSION_BUS_ADDRESS") == "" {
+		setenv(t, "DBUS_SESSION_BUS_ADDRESS", "unix:path=/dev/null")
+	}
+}
+
+// setenv sets an environment variable, and restores it when the test ends.
+func setenv(t *testing.T, key, value string) {
+	old, ok := os.LookupEnv(key)
+	t.Cleanup(func() {
+		if ok {
+			os.Setenv(key, old)
+		} else {
+			os.Unsetenv(key)
+		}
+	})
+	os.Setenv(key, value)
+}
+
+// stub writes a shell script that mimics a zenity executable: it reports
+// version, saves its arguments next to itself, and then runs script.
+func stub(t *testing.T, name, version, script string) string {
+	path := filepath.Join(t.TempDir(), name)
+	err := ioutil.WriteFile(path, []byte(`#!/bin/sh
+if [ "$1" = --version ]; then
+	echo `+version+`
+	exit 0
+fi
+printf '%s\0' "$@" > "$0.args"
+`+script), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// stubArgs returns the arguments of the last run of a stub.
+func stubArgs(t *testing.T, path string) []string {
+	out, err := ioutil.ReadFile(path + ".args")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSuffix(string(out), "\x00"), "\x00")
+}
+
+func hasArg(args []string, arg string) bool {
+	for _, a := range args {
+		if a == arg {
+			return true
+		}
+	}
+	return false
+}
+
+// argValue returns the value that follows flag in args.
+func argValue(args []string, flag string) string {
+	for i, a := range args {
+		if a == flag && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
+func TestBackends(t *testing.T) {
+	path := stub(t, "zenity", "3.32.0", "")
+
+	defer os.Setenv("ZENITY_EXECUTABLE", os.Getenv("ZENITY_EXECUTABLE"))
+	os.Setenv("ZENITY_EXECUTABLE", path)
+
+	backends := zenity.Backends()
+	if len(backends) == 0 {
+		t.Fatal("no backends")
+	}
+	want := zenity.Backend{Name: "zenity", Path: path, Version: "3.32.0"}
+	if backends[0] != want {
+		t.Errorf("Backends()[0] = %v, want %v", backends[0], want)
+	}
+}
+
+func TestExecutable(t *testing.T) {
+	fakeSession(t)
+	ok, err := zenity.Info("text", zenity.Executable(stub(t, "zenity", "3.32.0", "exit 0")))
+	if !ok || err != nil {
+		t.Errorf("Info() = %v, %v", ok, err)
+	}
+
+	ok, err = zenity.Info("text", zenity.Executable(stub(t, "zenity", "3.32.0", "exit 1")))
+	if ok || err != nil {
+		t.Errorf("Info() = %v, %v", ok, err)
+	}
+}
+
+func TestSupports(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		feature zenity.Feature
+		want    bool
+	}{
+		{"zenity", "3.32.0", zenity.IconNameFeature, true},
+		{"zenity", "3.2", zenity.ExtraButtonFeature, false},
+		{"zenity", "3.10", zenity.EllipsizeFeature, false},
+		{"zenity", "3.18.1", zenity.EllipsizeFeature, true},
+		{"qarma", "1.0", zenity.IconNameFeature, false},
+		{"matedialog", "1.24.0", zenity.ExtraButtonFeature, true},
+		{"matedialog", "1.24.0", zenity.EllipsizeFeature, false},
+		{"matedialog", "1.24.0", zenity.WindowClassFeature, true},
+		{"qarma", "1.0", zenity.WindowClassFeature, false},
+		{"zenity", "4.0.1", zenity.WindowClassFeature, false},
+	}
+	for _, tt := range tests {
+		path := stub(t, tt.name, tt.version, "")
+		if got := zenity.Supports(tt.feature, zenity.Executable(path)); got != tt.want {
+			t.Errorf("Supports(%v) with %s %s = %v, want %v", tt.feature, tt.name, tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestMessageDegrade(t *testing.T) {
+	fakeSession(t)
+	path := stub(t, "matedialog", "1.16.0", "exit 0")
+
+	_, err := zenity.Warning("text", zenity.Executable(path),
+		zenity.Icon(zenity.WarningIcon), zenity.Ellipsize())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args := stubArgs(t, path)
+	if hasArg(args, "--ellipsize") || hasArg(args, "--icon-name=dialog-warning") {
+		t.Errorf("unsupported arguments passed: %q", args)
+	}
+	if !hasArg(args, "--window-icon=warning") {
+		t.Errorf("supported arguments dropped: %q", args)
+	}
+}
+
+func TestMessageExtraButtonList(t *testing.T) {
+	fakeSession(t)
+	path := stub(t, "matedialog", "1.16.0", "echo Later")
+
+	_, err := zenity.Question("Save <file> & quit?", zenity.Executable(path), zenity.ExtraButton("Later"))
+	if err != zenity.ErrExtraButton {
+		t.Errorf("Question() = %v, want ErrExtraButton", err)
+	}
+
+	args := stubArgs(t, path)
+	if !hasArg(args, "--list") || hasArg(args, "--extra-button") {
+		t.Errorf("extra button not emulated: %q", args)
+	}
+	if !hasArg(args, "Save &lt;file&gt; &amp; quit?") {
+		t.Errorf("text not escaped: %q", args)
+	}
+	if !hasArg(args, "Yes") || hasArg(args, "OK") {
+		t.Errorf("question not answered with Yes: %q", args)
+	}
+}
+
+func TestAttach(t *testing.T) {
+	fakeSession(t)
+	defer os.Setenv("WINDOWID", os.Getenv("WINDOWID"))
+	os.Setenv("WINDOWID", "4242")
+
+	tests := []struct {
+		version string
+		options []zenity.Option
+		attach  string
+		modal   bool
+	}{
+		{"3.32.0", []zenity.Option{zenity.Attach(1234)}, "1234", false},
+		{"3.32.0", []zenity.Option{zenity.Modal()}, "4242", true},
+		{"3.32.0", []zenity.Option{zenity.Attach(0), zenity.Modal()}, "4242", true},
+		{"4.0.1", []zenity.Option{zenity.Attach(1234), zenity.Modal()}, "", true},
+	}
+	for _, tt := range tests {
+		path := stub(t, "zenity", tt.version, "")
+
+		_, err := zenity.Info("text", append(tt.options, zenity.Executable(path))...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		args := stubArgs(t, path)
+		if hasArg(args, "--attach") != (tt.attach != "") || tt.attach != "" && !hasArg(args, tt.attach) {
+			t.Errorf("zenity %s: want --attach %s: %q", tt.version, tt.attach, args)
+		}
+		if hasArg(args, "--modal") != tt.modal {
+			t.Errorf("zenity %s: want --modal %v: %q", tt.version, tt.modal, args)
+		}
+	}
+}
+
+func TestGeometry(t *testing.T) {
+	fakeSession(t)
+	for _, version := range []string{"3.32.0", "4.0.1"} {
+		path := stub(t, "zenity", version, "")
+
+		_, err := zenity.Info("text", zenity.Executable(path),
+			zenity.Width(640), zenity.Height(480), zenity.WindowClass("app", "App"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		args := stubArgs(t, path)
+		legacy := !zenity.VersionAtLeast(version, "4")
+		if hasArg(args, "--width") != legacy || hasArg(args, "640") != legacy || hasArg(args, "--height") != legacy {
+			t.Errorf("zenity %s: unexpected geometry: %q", version, args)
+		}
+		if hasArg(args, "--name") != legacy || hasArg(args, "app") != legacy ||
+			hasArg(args, "--class") != legacy || hasArg(args, "App") != legacy {
+			t.Errorf("zenity %s: unexpected window class: %q", version, args)
+		}
+	}
+}
+
+func TestSensitive(t *testing.T) {
+	fakeSession(t)
+	path := stub(t, "zenity", "3.32.0", `cat > "$0.stdin"`)
+
+	_, err := zenity.Info("Your password is hunter2.", zenity.Executable(path), zenity.Sensitive())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, arg := range stubArgs(t, path) {
+		if strings.Contains(arg, "hunter2") {
+			t.Errorf("sensitive argument: %q", arg)
+		}
+	}
+	if stdin, _ := ioutil.ReadFile(path + ".stdin"); string(stdin) != "Your password is hunter2." {
+		t.Errorf("stdin = %q", stdin)
+	}
+}
+
+func TestMinimalEnvironment(t *testing.T) {
+	path := stub(t, "zenity", "3.32.0", `env > "$0.env"`)
+
+	defer os.Setenv("DISPLAY", os.Getenv("DISPLAY"))
+	defer os.Unsetenv("ZENITY_TEST_SECRET")
+	os.Setenv("DISPLAY", ":42")
+	os.Setenv("ZENITY_TEST_SECRET", "hunter2")
+
+	_, err := zenity.Info("text", zenity.Executable(path), zenity.MinimalEnvironment())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env, err := ioutil.ReadFile(path + ".env")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(env), "DISPLAY=:42\n") || strings.Contains(string(env), "hunter2") {
+		t.Errorf("unexpected environment: %q", env)
+	}
+}
+
+func TestDisplayEnv(t *testing.T) {
+	path := stub(t, "zenity", "3.32.0", `env > "$0.env"`)
+
+	defer os.Setenv("WAYLAND_DISPLAY", os.Getenv("WAYLAND_DISPLAY"))
+	os.Setenv("WAYLAND_DISPLAY", "wayland-0")
+
+	_, err := zenity.Info("text", zenity.Executable(path),
+		zenity.Display(":5"), zenity.Locale("pt-PT"),
+		zenity.Env("GTK_THEME", "Adwaita:dark"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadFile(path + ".env")
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := strings.Split(string(data), "\n")
+	for _, kv := range []string{"DISPLAY=:5", "LC_ALL=pt_PT.UTF-8", "LANGUAGE=pt_PT", "GTK_THEME=Adwaita:dark"} {
+		if !hasArg(env, kv) {
+			t.Errorf("missing %s: %q", kv, env)
+		}
+	}
+	if strings.Contains(string(data), "WAYLAND_DISPLAY=") {
+		t.Errorf("unexpected WAYLAND_DISPLAY: %q", env)
+	}
+}
+
+func TestLocaleLanguage(t *testing.T) {
+	fakeSession(t)
+	path := stub(t, "zenity", "3.32.0", `env > "$0.env"`)
+	setenv(t, "LANG", "en_US.UTF-8")
+	setenv(t, "LC_ALL", "en_US.UTF-8")
+
+	_, err := zenity.Info("text", zenity.Executable(path), zenity.Locale("de"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadFile(path + ".env")
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := strings.Split(string(data), "\n")
+	for _, kv := range []string{"LANG=en_US.UTF-8", "LC_ALL=en_US.UTF-8", "LANGUAGE=de"} {
+		if !hasArg(env, kv) {
+			t.Errorf("missing %s: %q", kv, env)
+		}
+	}
+}
+
+func TestHasDisplay(t *testing.T) {
+	for _, key := range []string{"DISPLAY", "WAYLAND_DISPLAY", "XDG_RUNTIME_DIR", "DBUS_SESSION_BUS_ADDRESS"} {
+		defer os.Setenv(key, os.Getenv(key))
+		os.Unsetenv(key)
+	}
+	runtime := t.TempDir()
+	os.Setenv("XDG_RUNTIME_DIR", runtime)
+
+	if zenity.HasDisplay() {
+		t.Error("HasDisplay() = true without a display")
+	}
+	_, err := zenity.Info("text", zenity.Executable(stub(t, "zenity", "3.32.0", "")))
+	if !errors.Is(err, zenity.ErrNoDisplay) || !strings.Contains(err.Error(), "wayland-0") {
+		t.Errorf("Info() = %v, want ErrNoDisplay", err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(runtime, "wayland-0"), nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if !zenity.HasDisplay() {
+		t.Error("HasDisplay() = false with a Wayland socket")
+	}
+	err = zenity.Notify("text", zenity.Executable(stub(t, "zenity", "3.32.0", "")))
+	if !errors.Is(err, zenity.ErrNoDisplay) || !strings.Contains(err.Error(), "DBUS_SESSION_BUS_ADDRESS") {
+		t.Errorf("Notify() = %v, want ErrNoDisplay", err)
+	}
+
+	os.Setenv("DISPLAY", ":0")
+	os.Setenv("DBUS_SESSION_BUS_ADDRESS", "unix:path=/run/bus")
+	err = zenity.Notify("text", zenity.Executable(stub(t, "zenity", "3.32.0", "")))
+	if err != nil {
+		t.Errorf("Notify() = %v", err)
+	}
+}
+
+// brokenStub writes a program that is found, but can't be started.
+func brokenStub(t *testing.T, name string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := ioutil.WriteFile(path, []byte("#!/nonexistent/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestBackendChain(t *testing.T) {
+	fakeSession(t)
+	broken := brokenStub(t, "qarma")
+	working := stub(t, "zenity", "4.0.1", "exit 0")
+
+	var trace strings.Builder
+	var served zenity.Backend
+	ok, err := zenity.Info("text", zenity.Trace(&trace), zenity.ServedBy(&served),
+		zenity.IconName("face-smile"),
+		zenity.BackendChain("/nonexistent/kdialog", broken, working))
+	if !ok || err != nil {
+		t.Errorf("Info() = %v, %v", ok, err)
+	}
+	if served != (zenity.Backend{Name: "zenity", Path: working, Version: "4.0.1"}) {
+		t.Errorf("ServedBy() = %+v", served)
+	}
+	// Arguments are built for the program that displays the dialog.
+	if args := stubArgs(t, working); !hasArg(args, "--icon=face-smile") || hasArg(args, "--icon-name=face-smile") {
+		t.Errorf("arguments not built for zenity 4: %q", args)
+	}
+	if !strings.Contains(trace.String(), "qarma failed to launch: ") ||
+		!strings.Contains(trace.String(), "; trying zenity") ||
+		!strings.Contains(trace.String(), "dialog served by zenity 4.0.1") {
+		t.Errorf("unexpected trace: %q", trace.String())
+	}
+
+	// Programs that fail once started may have displayed the dialog.
+	crashed := stub(t, "qarma", "1.0", "exit 255")
+	working = stub(t, "zenity", "3.32.0", "exit 0")
+	_, err = zenity.Info("text", zenity.BackendChain(crashed, working))
+	if err == nil {
+		t.Error("Info() = nil, want exit status 255")
+	}
+	if _, err := os.Stat(working + ".args"); err == nil {
+		t.Error("dialog retried after a crash")
+	}
+
+	answered := stub(t, "qarma", "1.0", "exit 1")
+	working = stub(t, "zenity", "3.32.0", "exit 0")
+	ok, err = zenity.Info("text", zenity.BackendChain(answered, working))
+	if ok || err != nil {
+		t.Errorf("Info() = %v, %v", ok, err)
+	}
+	if _, err := os.Stat(working + ".args"); err == nil {
+		t.Error("dialog retried after an answer")
+	}
+
+	// Programs that don't accept zenity's arguments are skipped.
+	kdialog := stub(t, "kdialog", "21.12.3", "exit 1")
+	working = stub(t, "zenity", "3.32.0", "exit 0")
+	trace.Reset()
+	ok, err = zenity.Info("text", zenity.Trace(&trace), zenity.BackendChain(kdialog, working))
+	if !ok || err != nil {
+		t.Errorf("Info() = %v, %v", ok, err)
+	}
+	if _, err := os.Stat(kdialog + ".args"); err == nil {
+		t.Error("kdialog was given zenity's arguments")
+	}
+	if !strings.Contains(trace.String(), "kdialog skipped: ") {
+		t.Errorf("unexpected trace: %q", trace.String())
+	}
+	_, err = zenity.Info("text", zenity.BackendChain(kdialog))
+	if !errors.Is(err, exec.ErrNotFound) {
+		t.Errorf("Info() = %v, want ErrNotFound", err)
+	}
+
+	// Without a BackendChain, there is no fallback.
+	dir := t.TempDir()
+	for _, path := range []string{brokenStub(t, "qarma"), stub(t, "zenity", "3.32.0", "exit 0")} {
+		if err := os.Symlink(path, filepath.Join(dir, filepath.Base(path))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	setenv(t, "PATH", dir)
+	setenv(t, "ZENITY_EXECUTABLE", "")
+	served = zenity.Backend{}
+	_, err = zenity.Info("text", zenity.ServedBy(&served))
+	if err == nil || served != (zenity.Backend{}) {
+		t.Errorf("Info() = %v, served by %+v", err, served)
+	}
+}
+
+func TestRawArgs(t *testing.T) {
+	fakeSession(t)
+	path := stub(t, "zenity", "3.32.0", "")
+
+	var trace strings.Builder
+	_, err := zenity.Info("text", zenity.Executable(path), zenity.Trace(&trace),
+		zenity.RawArgs("zenity", "--icon-name=face-smile"),
+		zenity.RawArgs("yad", "--undecorated"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args := stubArgs(t, path)
+	if args[len(args)-1] != "--icon-name=face-smile" || hasArg(args, "--undecorated") {
+		t.Errorf("unexpected arguments: %q", args)
+	}
+	if !strings.Contains(trace.String(), `warning: raw arguments for yad ignored by zenity: ["--undecorated"]`) {
+		t.Errorf("unexpected trace: %q", trace.String())
+	}
+}
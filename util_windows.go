@@ -59,6 +59,13 @@ func activate() {
 	}
 }
 
+func ownerWindow(opts options) uintptr {
+	if opts.attach {
+		return uintptr(opts.windowID)
+	}
+	return 0
+}
+
 func commDlgError() error {
 	s, _, _ := commDlgExtendedError.Call()
 	if s == 0 {
@@ -167,3 +174,11 @@ type _IUnknownVtbl struct {
 	AddRef         uintptr
 	Release        uintptr
 }
+
+func iconDir(opts options) string { return "" }
+
+func hostPath(opts options, path string) string { return path }
+
+func backends() []Backend {
+	return nil
+}
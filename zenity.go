@@ -13,6 +13,10 @@ package zenity
 import (
 	"context"
 	"image/color"
+	"io"
+	"time"
+
+	"github.com/ncruces/zenity/internal/zenutil"
 )
 
 type constError string
@@ -21,7 +25,28 @@ func (e constError) Error() string { return string(e) }
 
 type options struct {
 	// General options
-	title string
+	title       string
+	timeout     time.Duration
+	executable  string
+	attach      bool
+	windowID    int
+	modal       bool
+	width       int
+	height      int
+	name        string
+	class       string
+	sensitive   bool
+	minimalEnv  bool
+	desktopUser bool
+	display     string
+	locale      string
+	env         []string
+	chain       []string
+	servedBy    *Backend
+	backend     zenutil.Backend // the program being tried, on Unix
+	rawArgs     []rawArgs
+	trace       io.Writer
+	strict      bool
 
 	// File selection options
 	filename         string
@@ -30,19 +55,38 @@ type options struct {
 	confirmCreate    bool
 	showHidden       bool
 	fileFilters      []FileFilter
+	separator        string
 
 	// Color selection options
 	color       color.Color
 	showPalette bool
 
 	// Message options
-	icon          DialogIcon
+	icon          interface{}
+	windowIcon    interface{}
 	okLabel       string
 	cancelLabel   string
 	extraButton   string
 	noWrap        bool
 	ellipsize     bool
 	defaultCancel bool
+	markup        bool
+	detail        string
+	remember      string
+
+	// Entry options
+	attempts int
+
+	// Default answer on timeout
+	timeoutDefault bool
+	timeoutAnswer  bool
+
+	// Queue options
+	priority int
+
+	// Crash report options
+	exit     bool
+	exitCode int
 
 	// Context for timeout
 	ctx context.Context
@@ -70,6 +114,126 @@ func Title(title string) Option {
 	return funcOption(func(o *options) { o.title = title })
 }
 
+// Timeout returns an Option to set a timeout after which the dialog is
+// dismissed.
+//
+// Dialogs dismissed by the timeout return context.DeadlineExceeded.
+func Timeout(timeout time.Duration) Option {
+	return funcOption(func(o *options) { o.timeout = timeout })
+}
+
+// Executable returns an Option to set the program used to display the dialog
+// (Unix only).
+//
+// The program must accept the same arguments as zenity. By default, the
+// program named by the ZENITY_EXECUTABLE environment variable is used, or else
+// the first one of qarma, zenity or matedialog found in the PATH.
+func Executable(path string) Option {
+	return funcOption(func(o *options) { o.executable = path })
+}
+
+// RawArgs returns an Option to pass additional arguments to the program that
+// displays the dialog, if it is tool, e.g. "zenity" or "qarma" (Unix only).
+//
+// RawArgs reaches features this package doesn't model. The arguments are
+// appended as is, after those for the options of the dialog, and ignored if
+// the dialog is displayed by another program.
+func RawArgs(tool string, args ...string) Option {
+	return funcOption(func(o *options) {
+		o.rawArgs = append(o.rawArgs, rawArgs{tool, args})
+	})
+}
+
+type rawArgs struct {
+	tool string
+	args []string
+}
+
+// Attach returns an Option to attach the dialog to a parent window
+// (Unix and Windows only).
+//
+// On Unix, id is an X11 window ID; on Windows, it is a window handle.
+// On Unix, if id is zero, the WINDOWID environment variable, exported by most
+// terminal emulators, is used instead.
+func Attach(id int) Option {
+	return funcOption(func(o *options) { o.attach = true; o.windowID = id })
+}
+
+// Modal returns an Option to make the dialog modal (Unix and Windows only).
+//
+// On Unix, unless Attach is also used, the dialog is attached to the window
+// given by the WINDOWID environment variable, if any.
+func Modal() Option {
+	return funcOption(func(o *options) { o.modal = true })
+}
+
+// Width returns an Option to set the dialog width in pixels (Unix only).
+func Width(width int) Option {
+	return funcOption(func(o *options) { o.width = width })
+}
+
+// Height returns an Option to set the dialog height in pixels (Unix only).
+func Height(height int) Option {
+	return funcOption(func(o *options) { o.height = height })
+}
+
+// WindowClass returns an Option to set the window manager name and class of
+// the dialog window (Unix only).
+//
+// It is ignored by programs that don't support it, such as zenity 4 and qarma.
+// See WindowClassFeature.
+func WindowClass(name, class string) Option {
+	return funcOption(func(o *options) { o.name = name; o.class = class })
+}
+
+// Sensitive returns an Option to keep the text of a message dialog off the
+// command line of the program that displays it (Unix only).
+//
+// On Unix, the command line can be read by other local users. Sensitive
+// message dialogs pass their text over stdin, and are displayed as scrollable
+// text dialogs. Other dialogs don't support it.
+func Sensitive() Option {
+	return funcOption(func(o *options) { o.sensitive = true })
+}
+
+// MinimalEnvironment returns an Option to run the program that displays the
+// dialog with only the environment variables needed to reach the display,
+// the session bus and the locale, instead of the whole environment
+// (Unix only).
+func MinimalEnvironment() Option {
+	return funcOption(func(o *options) { o.minimalEnv = true })
+}
+
+// DesktopUser returns an Option to display the dialog on the desktop of the
+// logged-in user, when running as root or under sudo (Unix only).
+//
+// The user is given by the SUDO_UID or PKEXEC_UID environment variables,
+// or else is the first one found with a process in a graphical session.
+// The dialog program runs as that user, with the display, session bus and
+// locale environment of their session.
+func DesktopUser() Option {
+	return funcOption(func(o *options) { o.desktopUser = true })
+}
+
+// Backend describes a program used to display dialogs.
+type Backend struct {
+	Name    string // the program name, e.g. "zenity"
+	Path    string // the path to the executable
+	Version string // the version reported by the program, if any
+}
+
+// Backends returns the programs that can be used to display dialogs, in order
+// of preference.
+//
+// On Unix, these are found in the PATH each time it changes. Inside a Flatpak
+// sandbox, or a toolbox or distrobox container, without any of these, those
+// on the host are used, through flatpak-spawn or distrobox-host-exec; paths
+// selected on the host are translated to paths visible in the sandbox.
+// On Windows, dialogs are displayed natively, and Backends returns nil.
+func Backends() []Backend {
+	return backends()
+}
+
 // DialogIcon is the enumeration for dialog icons.
 type DialogIcon int
 
@@ -82,8 +246,16 @@ const (
 )
 
 // Icon returns an Option to set the dialog icon.
+//
+// See also IconName, IconFile and IconImage.
 func Icon(icon DialogIcon) Option {
-	return funcOption(func(o *options) { o.icon = icon })
+	return funcOption(func(o *options) {
+		if icon == 0 {
+			o.icon = nil
+		} else {
+			o.icon = icon
+		}
+	})
 }
 
 // Context returns an Option to set a Context that can dismiss the dialog.
@@ -92,3 +264,18 @@ func Icon(icon DialogIcon) Option {
 func Context(ctx context.Context) Option {
 	return funcOption(func(o *options) { o.ctx = ctx })
 }
+
+// withTimeout emulates the timeout option with a Context,
+// for dialogs that don't support it natively.
+func withTimeout(opts *options) context.CancelFunc {
+	if opts.timeout <= 0 {
+		return func() {}
+	}
+	ctx := opts.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, opts.timeout)
+	opts.ctx = ctx
+	return cancel
+}